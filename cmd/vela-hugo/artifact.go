@@ -0,0 +1,361 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// tar.gz format for the artifact.
+	_artifactTarGz = "tar.gz"
+	// zip format for the artifact.
+	_artifactZip = "zip"
+)
+
+// Artifact represents the plugin configuration for packaging the site.
+type Artifact struct {
+	// enables packaging the output directory into an artifact
+	Enabled bool
+	// format of the artifact
+	Format string
+	// filesystem path to write the artifact to
+	Path string
+}
+
+// Validate verifies the Artifact is properly configured.
+func (a *Artifact) Validate() error {
+	logrus.Trace("validating artifact configuration")
+
+	v := new(ValidationError)
+
+	// check if the artifact is enabled
+	if !a.Enabled {
+		return nil
+	}
+
+	switch a.Format {
+	case _artifactTarGz, _artifactZip:
+	default:
+		v.add("artifact_format", fmt.Sprintf("use %s or %s", _artifactTarGz, _artifactZip),
+			"invalid artifact format provided: %s (supported: %s, %s)", a.Format, _artifactTarGz, _artifactZip)
+	}
+
+	return v.err()
+}
+
+// path returns the filesystem path the artifact is written to,
+// which defaults to the name of the output directory next to it.
+func (a *Artifact) path(output string) string {
+	if len(a.Path) > 0 {
+		return a.Path
+	}
+
+	output = filepath.Clean(output)
+
+	return filepath.Join(filepath.Dir(output), fmt.Sprintf("%s.%s", filepath.Base(output), a.Format))
+}
+
+// Exec packages the output directory into the artifact, writes its
+// checksum alongside and reports the artifact in the step outputs.
+func (a *Artifact) Exec(output string) error {
+	path := a.path(output)
+
+	logrus.Infof("packaging %s into artifact %s", output, path)
+
+	// reject an artifact written into the directory being packaged
+	rel, err := filepath.Rel(output, path)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("artifact %s must be written outside the output directory %s", path, output)
+	}
+
+	file, err := appFS.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(file, hash)
+
+	switch a.Format {
+	case _artifactZip:
+		err = writeZip(w, output)
+	default:
+		err = writeTarGz(w, output)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to package artifact %s: %w", path, err)
+	}
+
+	// verify the artifact was completely written before publishing its checksum
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to write artifact %s: %w", path, err)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	// write the checksum in the format used by sha256sum
+	err = afero.WriteFile(appFS, path+".sha256", fmt.Appendf(nil, "%s  %s\n", sum, filepath.Base(path)), 0644)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("artifact %s has sha256 %s", path, sum)
+
+	return writeOutputs(map[string]string{
+		"HUGO_ARTIFACT":        path,
+		"HUGO_ARTIFACT_SHA256": sum,
+	})
+}
+
+// archiveFile represents a file packaged into the artifact.
+type archiveFile struct {
+	// slash separated path relative to the output directory
+	name string
+	// filesystem path to the file
+	path string
+	// information for the file
+	info fs.FileInfo
+	// target of the file when it's a symlink
+	link string
+}
+
+// symlink returns whether the file is a symlink.
+func (f *archiveFile) symlink() bool {
+	return f.info.Mode()&fs.ModeSymlink != 0
+}
+
+// archiveFiles returns the files in the directory in lexical order.
+func archiveFiles(dir string) ([]*archiveFile, error) {
+	var files []*archiveFile
+
+	// walk visits the files in lexical order which keeps the artifact deterministic
+	err := afero.Walk(appFS, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		file := &archiveFile{name: filepath.ToSlash(rel), path: path, info: info}
+
+		// capture the target for symlinks which are packaged as links
+		if file.symlink() {
+			reader, ok := appFS.(afero.LinkReader)
+			if !ok {
+				logrus.Warnf("skipping symlink %s which can't be read", path)
+
+				return nil
+			}
+
+			file.link, err = reader.ReadlinkIfPossible(path)
+			if err != nil {
+				logrus.Warnf("skipping symlink %s: %v", path, err)
+
+				return nil
+			}
+		}
+
+		files = append(files, file)
+
+		return nil
+	})
+
+	return files, err
+}
+
+// archiveTime returns the timestamp for every file in the artifact, which
+// honors SOURCE_DATE_EPOCH and otherwise uses the earliest zip timestamp.
+//
+// https://reproducible-builds.org/docs/source-date-epoch/
+func archiveTime() time.Time {
+	epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64)
+	if err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+
+	return time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// archiveMode returns the normalized permissions for the file in the artifact.
+func archiveMode(info fs.FileInfo) int64 {
+	if info.IsDir() || info.Mode()&0111 != 0 {
+		return 0755
+	}
+
+	return 0644
+}
+
+// writeTarGz writes the files in the directory as a deterministic tar.gz archive.
+func writeTarGz(w io.Writer, dir string) error {
+	files, err := archiveFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	// leave the gzip header empty to avoid embedding a name or timestamp
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	modified := archiveTime()
+
+	for _, file := range files {
+		header := &tar.Header{
+			Name:     file.name,
+			Mode:     archiveMode(file.info),
+			ModTime:  modified,
+			Typeflag: tar.TypeReg,
+			Size:     file.info.Size(),
+			Format:   tar.FormatPAX,
+		}
+
+		switch {
+		case file.info.IsDir():
+			header.Name += "/"
+			header.Typeflag = tar.TypeDir
+			header.Size = 0
+		case file.symlink():
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = file.link
+			header.Mode = 0777
+			header.Size = 0
+		}
+
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			err = copyFile(tw, file.path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// writeZip writes the files in the directory as a deterministic zip archive.
+func writeZip(w io.Writer, dir string) error {
+	files, err := archiveFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	modified := archiveTime()
+
+	for _, file := range files {
+		header := &zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		}
+
+		header.SetMode(fs.FileMode(archiveMode(file.info)))
+
+		switch {
+		case file.info.IsDir():
+			header.Name += "/"
+			header.Method = zip.Store
+			header.SetMode(fs.ModeDir | 0755)
+		case file.symlink():
+			header.Method = zip.Store
+			header.SetMode(fs.ModeSymlink | 0777)
+		}
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case file.info.IsDir():
+		case file.symlink():
+			// zip stores the target of a symlink as its contents
+			_, err = io.WriteString(fw, file.link)
+			if err != nil {
+				return err
+			}
+		default:
+			err = copyFile(fw, file.path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+// copyFile copies the contents of the file to the writer.
+func copyFile(w io.Writer, path string) error {
+	file, err := appFS.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+
+	return err
+}
+
+// writeOutputs appends the values to the step outputs provided by Vela.
+//
+// https://go-vela.github.io/docs/usage/outputs/
+func writeOutputs(values map[string]string) error {
+	path := os.Getenv("VELA_OUTPUTS")
+
+	// check if step outputs are supported by the build
+	if len(path) == 0 {
+		logrus.Debug("no VELA_OUTPUTS provided - skipping step outputs")
+
+		return nil
+	}
+
+	file, err := appFS.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	for _, key := range sortedKeys(values) {
+		_, err = fmt.Fprintf(file, "%s=%s\n", key, values[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestArtifact_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure  bool
+		name     string
+		artifact Artifact
+	}{
+		{
+			failure:  false,
+			name:     "artifact disabled",
+			artifact: Artifact{Format: "rar"},
+		},
+		{
+			failure:  false,
+			name:     "tar.gz artifact",
+			artifact: Artifact{Enabled: true, Format: _artifactTarGz},
+		},
+		{
+			failure:  false,
+			name:     "zip artifact",
+			artifact: Artifact{Enabled: true, Format: _artifactZip},
+		},
+		{
+			failure:  true,
+			name:     "invalid artifact format",
+			artifact: Artifact{Enabled: true, Format: "rar"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.artifact.Validate()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s Validate should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Validate returned err: %v", test.name, err)
+		}
+	}
+}
+
+// setupSite creates the output directory for the site in the in mem file system.
+func setupSite(t *testing.T) *afero.Afero {
+	t.Helper()
+
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	for path, content := range map[string]string{
+		"/site/public/index.html":      "<html></html>",
+		"/site/public/css/main.css":    "body {}",
+		"/site/public/docs/index.html": "<html>docs</html>",
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+
+		// vary the timestamps which must not change the artifact
+		err = a.Chtimes(path, time.Now(), time.Now())
+		if err != nil {
+			t.Fatalf("unable to change times for %s: %v", path, err)
+		}
+	}
+
+	return a
+}
+
+func TestArtifact_Exec_TarGz(t *testing.T) {
+	// setup types
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	t.Setenv("VELA_OUTPUTS", "/vela/outputs/.env")
+
+	a := setupSite(t)
+
+	artifact := &Artifact{Enabled: true, Format: _artifactTarGz}
+
+	err := artifact.Exec("/site/public")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	first, err := a.ReadFile("/site/public.tar.gz")
+	if err != nil {
+		t.Fatalf("unable to read artifact: %v", err)
+	}
+
+	// package the site again after touching the files
+	setupSite(t)
+
+	err = artifact.Exec("/site/public")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	second, _ := a.ReadFile("/site/public.tar.gz")
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("Exec should write a deterministic artifact")
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("unable to read gzip: %v", err)
+	}
+
+	tr := tar.NewReader(gr)
+
+	var names []string
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("unable to read tar: %v", err)
+		}
+
+		if !header.ModTime.Equal(time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Exec wrote %s with timestamp %s", header.Name, header.ModTime)
+		}
+
+		names = append(names, header.Name)
+	}
+
+	want := []string{"css/", "css/main.css", "docs/", "docs/index.html", "index.html"}
+
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Exec artifact contains %v, want %v", names, want)
+	}
+
+	sum := sha256.Sum256(first)
+
+	checksum, _ := a.ReadFile("/site/public.tar.gz.sha256")
+	if got, want := string(checksum), hex.EncodeToString(sum[:])+"  public.tar.gz\n"; got != want {
+		t.Errorf("Exec checksum is %q, want %q", got, want)
+	}
+
+	outputs, _ := a.ReadFile("/vela/outputs/.env")
+	if !bytes.Contains(outputs, []byte("HUGO_ARTIFACT=/site/public.tar.gz\nHUGO_ARTIFACT_SHA256="+hex.EncodeToString(sum[:])+"\n")) {
+		t.Errorf("Exec outputs are %q", outputs)
+	}
+}
+
+func TestArtifact_Exec_Zip(t *testing.T) {
+	// setup types
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	t.Setenv("VELA_OUTPUTS", "")
+
+	a := setupSite(t)
+
+	artifact := &Artifact{Enabled: true, Format: _artifactZip, Path: "/build/site.zip"}
+
+	err := artifact.Exec("/site/public")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	data, err := a.ReadFile("/build/site.zip")
+	if err != nil {
+		t.Fatalf("unable to read artifact: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unable to read zip: %v", err)
+	}
+
+	var names []string
+
+	for _, file := range zr.File {
+		if !file.Modified.Equal(time.Unix(1700000000, 0)) {
+			t.Errorf("Exec wrote %s with timestamp %s", file.Name, file.Modified)
+		}
+
+		names = append(names, file.Name)
+	}
+
+	want := []string{"css/", "css/main.css", "docs/", "docs/index.html", "index.html"}
+
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Exec artifact contains %v, want %v", names, want)
+	}
+}
+
+func TestArtifact_Exec_InsideOutput(t *testing.T) {
+	setupSite(t)
+
+	artifact := &Artifact{Enabled: true, Format: _artifactZip, Path: "/site/public/site.zip"}
+
+	err := artifact.Exec("/site/public")
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifact_Exec_Symlink(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_OUTPUTS", "")
+
+	dir := t.TempDir()
+
+	// setup os file system which supports symlinks
+	appFS = afero.NewOsFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	output := filepath.Join(dir, "public")
+
+	err := a.MkdirAll(output, 0755)
+	if err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+
+	err = a.WriteFile(filepath.Join(output, "index.html"), []byte("<html></html>"), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = os.Symlink("index.html", filepath.Join(output, "latest.html"))
+	if err != nil {
+		t.Fatalf("unable to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "tar.gz", format: _artifactTarGz},
+		{name: "zip", format: _artifactZip},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, "site."+test.format)
+
+			artifact := &Artifact{Enabled: true, Format: test.format, Path: path}
+
+			err := artifact.Exec(output)
+			if err != nil {
+				t.Fatalf("Exec returned err: %v", err)
+			}
+
+			data, err := a.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unable to read artifact: %v", err)
+			}
+
+			links := map[string]string{}
+
+			switch test.format {
+			case _artifactTarGz:
+				gr, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("unable to read gzip: %v", err)
+				}
+
+				tr := tar.NewReader(gr)
+
+				for {
+					header, err := tr.Next()
+					if err == io.EOF {
+						break
+					}
+
+					if err != nil {
+						t.Fatalf("unable to read tar: %v", err)
+					}
+
+					if header.Typeflag == tar.TypeSymlink {
+						links[header.Name] = header.Linkname
+					}
+				}
+			case _artifactZip:
+				zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+				if err != nil {
+					t.Fatalf("unable to read zip: %v", err)
+				}
+
+				for _, file := range zr.File {
+					if file.Mode()&fs.ModeSymlink == 0 {
+						continue
+					}
+
+					rc, err := file.Open()
+					if err != nil {
+						t.Fatalf("unable to open %s: %v", file.Name, err)
+					}
+
+					target, _ := io.ReadAll(rc)
+					rc.Close()
+
+					links[file.Name] = string(target)
+				}
+			}
+
+			want := map[string]string{"latest.html": "index.html"}
+
+			if !reflect.DeepEqual(links, want) {
+				t.Errorf("Exec artifact links are %v, want %v", links, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// size with an optional unit, e.g. 200KB or 1.5 MB.
+	sizePattern = regexp.MustCompile(`(?i)^\s*(\d+(?:\.\d+)?)\s*(b|kb|kib|mb|mib|gb|gib)?\s*$`)
+	// budget rule for a glob pattern, e.g. **/*.js < 200KB.
+	rulePattern = regexp.MustCompile(`^\s*(\S+)\s*<=?\s*(.+)$`)
+)
+
+// Budget represents a size limit for the files in the output directory.
+type Budget struct {
+	// rule the budget was parsed from
+	Rule string
+	// glob pattern matching the files the budget applies to
+	Pattern string
+	// maximum size in bytes
+	Limit int64
+	// limit applies to the total size of the site instead of each file
+	Total bool
+
+	match *regexp.Regexp
+}
+
+// Offender represents a file which exceeds a size budget.
+type Offender struct {
+	// budget which was exceeded
+	Budget *Budget
+	// slash separated path relative to the output directory
+	Path string
+	// size in bytes of the file
+	Size int64
+}
+
+// budgets parses the size budgets configured for the output directory.
+func (o *Output) budgets() ([]*Budget, error) {
+	v := new(ValidationError)
+
+	var budgets []*Budget
+
+	// check if a total size budget is provided
+	if len(o.TotalBudget) > 0 {
+		limit, err := parseSize(o.TotalBudget)
+		if err != nil {
+			v.add("budget_total_size", "use a size like 10MB", "%v", err)
+		} else {
+			budgets = append(budgets, &Budget{Rule: "total < " + o.TotalBudget, Limit: limit, Total: true})
+		}
+	}
+
+	// check if a file size budget is provided
+	if len(o.FileBudget) > 0 {
+		limit, err := parseSize(o.FileBudget)
+		if err != nil {
+			v.add("budget_file_size", "use a size like 1MB", "%v", err)
+		} else {
+			budgets = append(budgets, &Budget{Rule: "** < " + o.FileBudget, Pattern: "**", Limit: limit, match: globRegexp("**")})
+		}
+	}
+
+	for _, rule := range o.Budgets {
+		budget, err := parseBudget(rule)
+		if err != nil {
+			v.add("budgets", "use a rule like **/*.js < 200KB", "%v", err)
+
+			continue
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, v.err()
+}
+
+// parseBudget parses the budget from a rule like **/*.js < 200KB.
+func parseBudget(rule string) (*Budget, error) {
+	match := rulePattern.FindStringSubmatch(rule)
+	if match == nil {
+		return nil, fmt.Errorf("invalid budget rule %q", rule)
+	}
+
+	limit, err := parseSize(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid budget rule %q: %w", rule, err)
+	}
+
+	return &Budget{
+		Rule:    strings.TrimSpace(rule),
+		Pattern: match[1],
+		Limit:   limit,
+		match:   globRegexp(match[1]),
+	}, nil
+}
+
+// parseSize parses the size in bytes from a value like 200KB, which
+// uses the same 1024 byte multiples the sizes are reported in.
+func parseSize(value string) (int64, error) {
+	match := sizePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+
+	size, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", value, err)
+	}
+
+	switch strings.ToLower(match[2]) {
+	case "kb", "kib":
+		size *= 1 << 10
+	case "mb", "mib":
+		size *= 1 << 20
+	case "gb", "gib":
+		size *= 1 << 30
+	}
+
+	return int64(math.Round(size)), nil
+}
+
+// globRegexp converts the glob pattern to a regular expression where
+// * matches within a path segment and ** matches across segments.
+func globRegexp(pattern string) *regexp.Regexp {
+	b := new(strings.Builder)
+
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// checkBudgets returns the files in the manifest which exceed the budgets.
+func checkBudgets(budgets []*Budget, m *Manifest) []*Offender {
+	var offenders []*Offender
+
+	for _, budget := range budgets {
+		// check if the budget applies to the site
+		if budget.Total {
+			if m.Size > budget.Limit {
+				offenders = append(offenders, &Offender{Budget: budget, Size: m.Size})
+			}
+
+			continue
+		}
+
+		for _, file := range m.Files {
+			if budget.match.MatchString(file.Path) && file.Size > budget.Limit {
+				offenders = append(offenders, &Offender{Budget: budget, Path: file.Path, Size: file.Size})
+			}
+		}
+	}
+
+	return offenders
+}
+
+// budgetErr prints the offenders as a table, since the log
+// output escapes newlines, and returns a summary error.
+func budgetErr(offenders []*Offender) error {
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "  RULE\tFILE\tSIZE\tLIMIT")
+
+	for _, offender := range offenders {
+		path := offender.Path
+		if offender.Budget.Total {
+			path = "(total)"
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", offender.Budget.Rule, path, formatSize(offender.Size), formatSize(offender.Budget.Limit))
+	}
+
+	w.Flush()
+
+	return fmt.Errorf("output exceeds the size budgets: %d offender(s) found", len(offenders))
+}
+
+// checkBudgets verifies the files in the manifest are within the size budgets.
+func (o *Output) checkBudgets(m *Manifest) error {
+	budgets, err := o.budgets()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("checking output against %d size budget(s)", len(budgets))
+
+	offenders := checkBudgets(budgets, m)
+	if len(offenders) > 0 {
+		return budgetErr(offenders)
+	}
+
+	return nil
+}
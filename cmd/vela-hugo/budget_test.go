@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		value   string
+		want    int64
+	}{
+		{failure: false, value: "512", want: 512},
+		{failure: false, value: "512B", want: 512},
+		{failure: false, value: "200KB", want: 200 * 1024},
+		{failure: false, value: "200 kib", want: 200 * 1024},
+		{failure: false, value: "1.5MB", want: 1536 * 1024},
+		{failure: false, value: "2GB", want: 2 * 1024 * 1024 * 1024},
+		{failure: true, value: "200 kilobytes"},
+		{failure: true, value: "-1KB"},
+		{failure: true, value: ""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseSize(test.value)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("parseSize for %q should have returned err", test.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseSize for %q returned err: %v", test.value, err)
+		}
+
+		if got != test.want {
+			t.Errorf("parseSize for %q is %d, want %d", test.value, got, test.want)
+		}
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "**/*.js", path: "main.js", want: true},
+		{pattern: "**/*.js", path: "js/vendor/main.js", want: true},
+		{pattern: "**/*.js", path: "js/main.json", want: false},
+		{pattern: "*.js", path: "js/main.js", want: false},
+		{pattern: "images/**", path: "images/logo/large.png", want: true},
+		{pattern: "images/**", path: "docs/images/logo.png", want: false},
+		{pattern: "docs/*/index.html", path: "docs/guide/index.html", want: true},
+		{pattern: "docs/*/index.html", path: "docs/guide/setup/index.html", want: false},
+		{pattern: "img-?.png", path: "img-1.png", want: true},
+		{pattern: "**", path: "index.html", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := globRegexp(test.pattern).MatchString(test.path)
+
+		if got != test.want {
+			t.Errorf("globRegexp %s matching %s is %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
+func TestParseBudget(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		rule    string
+		pattern string
+		limit   int64
+	}{
+		{failure: false, rule: "**/*.js < 200KB", pattern: "**/*.js", limit: 200 * 1024},
+		{failure: false, rule: "images/** <= 1MB", pattern: "images/**", limit: 1024 * 1024},
+		{failure: false, rule: "*.css<10KB", pattern: "*.css", limit: 10 * 1024},
+		{failure: true, rule: "**/*.js 200KB"},
+		{failure: true, rule: "**/*.js < lots"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseBudget(test.rule)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("parseBudget for %q should have returned err", test.rule)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseBudget for %q returned err: %v", test.rule, err)
+
+			continue
+		}
+
+		if got.Pattern != test.pattern || got.Limit != test.limit {
+			t.Errorf("parseBudget for %q is %s < %d, want %s < %d", test.rule, got.Pattern, got.Limit, test.pattern, test.limit)
+		}
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	// setup types
+	m := &Manifest{
+		Count: 3,
+		Size:  350 * 1024,
+		Files: []*ManifestFile{
+			{Path: "index.html", Size: 20 * 1024},
+			{Path: "js/app.js", Size: 250 * 1024},
+			{Path: "js/search.js", Size: 80 * 1024},
+		},
+	}
+
+	o := &Output{
+		Budgets:     []string{"**/*.js < 200KB", "**/*.html < 100KB"},
+		FileBudget:  "100KB",
+		TotalBudget: "300KB",
+	}
+
+	budgets, err := o.budgets()
+	if err != nil {
+		t.Fatalf("budgets returned err: %v", err)
+	}
+
+	var got []string
+
+	for _, offender := range checkBudgets(budgets, m) {
+		got = append(got, offender.Budget.Rule+" "+offender.Path)
+	}
+
+	want := []string{
+		"total < 300KB ",
+		"** < 100KB js/app.js",
+		"**/*.js < 200KB js/app.js",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkBudgets is %q, want %q", got, want)
+	}
+}
+
+func TestOutput_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		output Output
+		want   []string
+	}{
+		{
+			output: Output{},
+		},
+		{
+			output: Output{Budgets: []string{"**/*.js < 200KB"}, FileBudget: "1MB", TotalBudget: "50MB"},
+		},
+		{
+			output: Output{Budgets: []string{"**/*.js < 200KB", "**/*.css"}, FileBudget: "1 megabyte", TotalBudget: "huge"},
+			want:   []string{"budget_total_size", "budget_file_size", "budgets"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		var got []string
+
+		err := test.output.Validate()
+		if err != nil {
+			for _, problem := range err.(*ValidationError).Problems {
+				got = append(got, problem.Parameter)
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Validate problems are %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestOutput_Exec_Budgets(t *testing.T) {
+	// setup filesystem
+	setupSite(t)
+
+	// setup tests
+	tests := []struct {
+		failure bool
+		output  Output
+	}{
+		{
+			failure: false,
+			output:  Output{Budgets: []string{"**/*.html < 1KB"}, TotalBudget: "1KB"},
+		},
+		{
+			failure: true,
+			output:  Output{Budgets: []string{"**/*.css < 5B"}},
+		},
+		{
+			failure: true,
+			output:  Output{TotalBudget: "20B"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.output.Exec(t.Context(), "/site/public", "/site", "")
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("Exec for %+v should have returned err", test.output)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Exec for %+v returned err: %v", test.output, err)
+		}
+	}
+}
@@ -2,14 +2,172 @@
 
 package main
 
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slugChars matches the characters replaced when converting a value into a URL path segment.
+var slugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// buildMetadata maps the fields available to the base URL template
+// to the environment variables Vela provides for the build.
+//
+// https://go-vela.github.io/docs/reference/environment/variables/
+var buildMetadata = map[string]string{
+	"Branch":      "VELA_BUILD_BRANCH",
+	"Commit":      "VELA_BUILD_COMMIT",
+	"Event":       "VELA_BUILD_EVENT",
+	"Number":      "VELA_BUILD_NUMBER",
+	"Org":         "VELA_REPO_ORG",
+	"PullRequest": "VELA_PULL_REQUEST",
+	"Repo":        "VELA_REPO_NAME",
+	"Tag":         "VELA_BUILD_TAG",
+}
+
 // Build represents the plugin configuration for Hugo information.
 type Build struct {
 	// hostname (and path) to the root, e.g. http://spf13.com/
 	BaseURL string
+	// allow a relative or protocol-relative base url, e.g. /docs/ or //example.com/
+	AllowRelative bool
 	// include content marked as draft
 	Draft bool
 	// include expired content
 	Expired bool
 	// include content with publishdate in the future
 	Future bool
+	// verify the site builds reproducibly by building it twice
+	Reproducible bool
+}
+
+// Validate verifies the Build is properly configured.
+func (b *Build) Validate() error {
+	logrus.Trace("validating build configuration")
+
+	v := new(ValidationError)
+
+	// render the base url from the build metadata
+	err := b.Render()
+	if err != nil {
+		v.add("base_url", "fix the template or the build metadata it references", "%v", err)
+
+		return v.err()
+	}
+
+	// check if a base url is provided
+	if len(b.BaseURL) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(b.BaseURL)
+	if err != nil {
+		v.add("base_url", "use an absolute url, e.g. https://example.com/", "invalid base url %s: %v", b.BaseURL, err)
+
+		return v.err()
+	}
+
+	switch {
+	case u.Scheme == "http" || u.Scheme == "https":
+		// verify a host is provided for the base url
+		if len(u.Host) == 0 {
+			v.add("base_url", "use an absolute url, e.g. https://example.com/", "invalid base url %s: no host provided", b.BaseURL)
+		}
+	case len(u.Scheme) > 0:
+		v.add("base_url", "use an http or https url", "invalid base url %s: unsupported scheme %s (supported: http, https)", b.BaseURL, u.Scheme)
+	case !strings.HasPrefix(b.BaseURL, "/"):
+		v.add("base_url", fmt.Sprintf("use an absolute url, e.g. https://%s", b.BaseURL), "invalid base url %s: must be an absolute url", b.BaseURL)
+	case !b.AllowRelative:
+		v.add("base_url", "set allow_relative_base_url to build with a relative url", "invalid base url %s: relative urls are not allowed", b.BaseURL)
+	}
+
+	// check if the base url is valid
+	if len(v.Problems) > 0 {
+		return v.err()
+	}
+
+	// Hugo expects the base url to end with a trailing slash
+	if len(u.RawQuery) == 0 && len(u.Fragment) == 0 && !strings.HasSuffix(b.BaseURL, "/") {
+		logrus.Debugf("adding trailing slash to base url %s", b.BaseURL)
+
+		b.BaseURL += "/"
+	}
+
+	return nil
+}
+
+// Compare warns when the base url overrides the baseURL from the site configuration.
+func (b *Build) Compare(site *SiteConfig) {
+	// check if both base urls are provided
+	if len(b.BaseURL) == 0 || len(site.BaseURL) == 0 {
+		return
+	}
+
+	if strings.TrimSuffix(b.BaseURL, "/") != strings.TrimSuffix(site.BaseURL, "/") {
+		logrus.Warnf("base url %s overrides baseURL %s from %s", b.BaseURL, site.BaseURL, site.source("baseurl"))
+	}
+}
+
+// Render renders the base URL as a template from the Vela build metadata.
+func (b *Build) Render() error {
+	// check if the base url is a template
+	if !strings.Contains(b.BaseURL, "{{") {
+		return nil
+	}
+
+	logrus.Trace("rendering base url from build metadata")
+
+	tmpl, err := template.New("base_url").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"slug": slug}).
+		Parse(b.BaseURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse base url template: %w", err)
+	}
+
+	// only capture the metadata provided for the build so
+	// a template referencing missing metadata fails
+	data := make(map[string]string)
+
+	for field, env := range buildMetadata {
+		if value := os.Getenv(env); len(value) > 0 {
+			data[field] = value
+		}
+	}
+
+	rendered := new(strings.Builder)
+
+	err = tmpl.Execute(rendered, data)
+	if err != nil {
+		return fmt.Errorf("unable to render base url template: %w", err)
+	}
+
+	baseURL := rendered.String()
+
+	// verify the rendered base url is an absolute url
+	u, err := url.Parse(baseURL)
+	if err != nil || !u.IsAbs() || len(u.Host) == 0 {
+		return fmt.Errorf("rendered base url %s is not an absolute url", baseURL)
+	}
+
+	if !strings.HasSuffix(u.Path, "/") {
+		return fmt.Errorf("rendered base url %s must end with a trailing slash", baseURL)
+	}
+
+	logrus.Infof("rendered base url %s", baseURL)
+
+	b.BaseURL = baseURL
+
+	return nil
+}
+
+// slug converts the value into a URL path segment, e.g. feature/Login => feature-login.
+func slug(value string) string {
+	return strings.Trim(slugChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
 }
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+)
+
+func TestBuild_Render(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_REPO_NAME", "docs")
+	t.Setenv("VELA_BUILD_BRANCH", "feature/Search-UI")
+	t.Setenv("VELA_PULL_REQUEST", "42")
+	t.Setenv("VELA_BUILD_TAG", "")
+
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		baseURL string
+		want    string
+	}{
+		{
+			failure: false,
+			name:    "no template",
+			baseURL: "https://docs.example.com/",
+			want:    "https://docs.example.com/",
+		},
+		{
+			failure: false,
+			name:    "pull request template",
+			baseURL: "https://docs.example.com/{{.Repo}}/pr-{{.PullRequest}}/",
+			want:    "https://docs.example.com/docs/pr-42/",
+		},
+		{
+			failure: false,
+			name:    "branch template with slug",
+			baseURL: "https://{{slug .Branch}}.preview.example.com/",
+			want:    "https://feature-search-ui.preview.example.com/",
+		},
+		{
+			failure: true,
+			name:    "missing build metadata",
+			baseURL: "https://docs.example.com/{{.Tag}}/",
+		},
+		{
+			failure: true,
+			name:    "malformed template",
+			baseURL: "https://docs.example.com/{{.Repo/",
+		},
+		{
+			failure: true,
+			name:    "relative url",
+			baseURL: "/{{.Repo}}/",
+		},
+		{
+			failure: true,
+			name:    "no trailing slash",
+			baseURL: "https://docs.example.com/{{.Repo}}",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		b := &Build{BaseURL: test.baseURL}
+
+		err := b.Render()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s Render should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Render returned err: %v", test.name, err)
+		}
+
+		if b.BaseURL != test.want {
+			t.Errorf("%s Render is %s, want %s", test.name, b.BaseURL, test.want)
+		}
+	}
+}
+
+func TestBuild_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		build   Build
+		want    string
+	}{
+		{
+			failure: false,
+			name:    "no base url",
+			build:   Build{},
+			want:    "",
+		},
+		{
+			failure: false,
+			name:    "absolute base url",
+			build:   Build{BaseURL: "https://docs.example.com/"},
+			want:    "https://docs.example.com/",
+		},
+		{
+			failure: false,
+			name:    "base url without trailing slash",
+			build:   Build{BaseURL: "http://docs.example.com/v1"},
+			want:    "http://docs.example.com/v1/",
+		},
+		{
+			failure: false,
+			name:    "relative base url allowed",
+			build:   Build{BaseURL: "/docs", AllowRelative: true},
+			want:    "/docs/",
+		},
+		{
+			failure: false,
+			name:    "protocol-relative base url allowed",
+			build:   Build{BaseURL: "//cdn.example.com/docs/", AllowRelative: true},
+			want:    "//cdn.example.com/docs/",
+		},
+		{
+			failure: true,
+			name:    "relative base url",
+			build:   Build{BaseURL: "/docs/"},
+		},
+		{
+			failure: true,
+			name:    "base url without scheme",
+			build:   Build{BaseURL: "docs.example.com/", AllowRelative: true},
+		},
+		{
+			failure: true,
+			name:    "unsupported scheme",
+			build:   Build{BaseURL: "ftp://docs.example.com/"},
+		},
+		{
+			failure: true,
+			name:    "no host",
+			build:   Build{BaseURL: "https:///docs/"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.build.Validate()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s Validate should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Validate returned err: %v", test.name, err)
+		}
+
+		if test.build.BaseURL != test.want {
+			t.Errorf("%s Validate is %s, want %s", test.name, test.build.BaseURL, test.want)
+		}
+	}
+}
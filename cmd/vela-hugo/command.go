@@ -23,11 +23,47 @@ func execCmd(e *exec.Cmd) error {
 	e.Stderr = os.Stderr
 
 	// output "trace" string for command
-	fmt.Println("$", strings.Join(e.Args, " "))
+	fmt.Println("$", trace(e))
 
 	return e.Run()
 }
 
+// outputCmd is a helper function to run the
+// provided command and capture its output.
+func outputCmd(e *exec.Cmd) ([]byte, error) {
+	logrus.Tracef("executing cmd %s", strings.Join(e.Args, " "))
+
+	// set command stderr to OS stderr
+	e.Stderr = os.Stderr
+
+	// output "trace" string for command
+	fmt.Println("$", trace(e))
+
+	return e.Output()
+}
+
+// trace is a helper function to format the command with
+// the environment variables it overrides for output.
+func trace(e *exec.Cmd) string {
+	// capture the environment inherited from the plugin
+	inherited := make(map[string]bool)
+
+	for _, env := range os.Environ() {
+		inherited[env] = true
+	}
+
+	var parts []string
+
+	for _, env := range e.Env {
+		// mask environment overrides which likely contain a secret
+		if !inherited[env] {
+			parts = append(parts, maskEnv(env))
+		}
+	}
+
+	return strings.Join(append(parts, e.Args...), " ")
+}
+
 // versionCmd is a helper function to output
 // the client and server version information.
 func versionCmd(ctx context.Context) *exec.Cmd {
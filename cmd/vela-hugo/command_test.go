@@ -3,6 +3,7 @@
 package main
 
 import (
+	"os"
 	"os/exec"
 	"testing"
 )
@@ -33,3 +34,51 @@ func Test_execCmd(t *testing.T) {
 		})
 	}
 }
+
+func Test_outputCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		command *exec.Cmd
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "should pass - valid command",
+			command: exec.CommandContext(t.Context(), "echo", "hello"),
+			want:    "hello\n",
+			wantErr: false,
+		},
+		{
+			name:    "should fail - invalid command",
+			command: exec.CommandContext(t.Context(), "foobar", "world"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// run the command and validate the results
+			got, err := outputCmd(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("outputCmd() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("outputCmd() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_trace(t *testing.T) {
+	// setup command with environment overrides
+	command := exec.CommandContext(t.Context(), "echo", "hello")
+	command.Env = append(os.Environ(), "HUGO_PARAMS_ENV=staging", "HUGOxPARAMSxAPI_TOKEN=secret")
+
+	want := "HUGO_PARAMS_ENV=staging HUGOxPARAMSxAPI_TOKEN=*** echo hello"
+
+	got := trace(command)
+
+	if got != want {
+		t.Errorf("trace is %s, want %s", got, want)
+	}
+}
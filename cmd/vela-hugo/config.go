@@ -4,8 +4,9 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -29,105 +30,169 @@ type Config struct {
 	OutputDirectory string
 	// filesystem path to read files from
 	SourceDirectory string
+	// site params to override through the environment
+	Params map[string]any
+	// site configuration to override with an additional config file
+	Overrides map[string]any
+	// read and validate the site configuration before building
+	ValidateSite bool
+	// print the effective configuration Hugo builds the site with
+	Print bool
+	// filesystem path to write the effective configuration to
+	PrintFile string
+	// filesystem path to the effective configuration to compare against
+	Baseline string
+
+	// filesystem path to the rendered config overrides
+	overlay string
 }
 
 // Validate verifies the Config is properly configured.
+//
+// Hugo resolves relative paths from the source directory,
+// so the paths are verified the same way Hugo reads them.
 func (c *Config) Validate() error {
 	logrus.Trace("validating config configuration")
 
-	// use custom filesystem which enables us to test
-	a := &afero.Afero{
-		Fs: appFS,
+	v := new(ValidationError)
+
+	// check if a source directory is provided
+	if len(c.SourceDirectory) > 0 {
+		// the other paths are resolved from the source directory
+		if !v.stat("source_directory", "source directory", c.SourceDirectory, "create the directory or update source_directory (relative to the workspace)") {
+			return v.err()
+		}
 	}
 
 	// check if a cache directory is provided
 	if len(c.CacheDirectory) > 0 {
-		// check if cache directory exists
-		_, err := a.Stat(c.CacheDirectory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no cache directory found @ %s", c.CacheDirectory)
-			}
-
-			return err
-		}
+		v.stat("cache_directory", "cache directory", c.resolve(c.CacheDirectory), c.suggestion("cache_directory", c.CacheDirectory))
 	}
 
 	// check if a config file is provided
 	if len(c.File) > 0 {
-		// verify config directory is provided
-		if len(c.Directory) == 0 {
-			return fmt.Errorf("no config directory provided")
+		for _, file := range strings.Split(c.File, ",") {
+			file = strings.TrimSpace(file)
+
+			// create path to config based off the source directory
+			v.stat("config_file", "config", c.resolve(file), c.fileSuggestion(file))
 		}
+	}
 
-		// check if config directory exists
-		_, err := a.Stat(c.Directory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no config directory found @ %s", c.Directory)
-			}
+	// check if a content directory is provided
+	if len(c.ContentDirectory) > 0 {
+		v.stat("content_directory", "content directory", c.resolve(c.ContentDirectory), c.suggestion("content_directory", c.ContentDirectory))
+	}
+
+	// check if a layout directory is provided
+	if len(c.LayoutDirectory) > 0 {
+		v.stat("layout_directory", "layout directory", c.resolve(c.LayoutDirectory), c.suggestion("layout_directory", c.LayoutDirectory))
+	}
 
-			return err
+	// check if site params are provided
+	if len(c.Params) > 0 {
+		// verify the keys for the site params
+		err := validateParams(c.Params)
+		if err != nil {
+			v.add("params", "rename the param keys to letters, numbers and underscores", "%v", err)
 		}
+	}
 
-		// create path to config based off directory and name
-		path := filepath.Join(c.Directory, c.File)
+	return v.err()
+}
 
-		// validate that the config file exists
-		_, err = a.Stat(path)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no config found @ %s", path)
-			}
+// suggestion returns the suggested fix for a directory parameter which
+// doesn't exist, suggesting the closest directory next to the path.
+func (c *Config) suggestion(parameter, path string) string {
+	// capture the directories next to the path
+	dirs := entries(filepath.Dir(c.resolve(path)), true)
 
-			return err
-		}
+	if match, ok := closest(filepath.Base(path), dirs); ok {
+		return fmt.Sprintf("did you mean %s: %s?", parameter, filepath.Join(filepath.Dir(path), match))
 	}
 
-	// check if a content directory is provided
-	if len(c.ContentDirectory) > 0 {
-		// check if content directory exists
-		_, err := a.Stat(c.ContentDirectory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no content directory found @ %s", c.ContentDirectory)
-			}
+	// check if the path is resolved from the source directory
+	if len(c.SourceDirectory) > 0 {
+		return fmt.Sprintf("create the directory or update %s (relative to source_directory)", parameter)
+	}
 
-			return err
-		}
+	return fmt.Sprintf("create the directory or update %s", parameter)
+}
+
+// fileSuggestion returns the suggested fix for a config file which doesn't
+// exist, suggesting the closest config file in the source or config directory.
+func (c *Config) fileSuggestion(file string) string {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
 	}
 
-	// check if a layout directory is provided
-	if len(c.LayoutDirectory) > 0 {
-		// check if layout directory exists
-		_, err := a.Stat(c.LayoutDirectory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no layout directory found @ %s", c.LayoutDirectory)
-			}
+	// check if the config file exists in the config directory
+	if len(c.Directory) > 0 && !filepath.IsAbs(file) {
+		candidate := filepath.Join(c.Directory, file)
 
-			return err
+		exists, err := a.Exists(c.resolve(candidate))
+		if err == nil && exists {
+			return fmt.Sprintf("config files are relative to the source directory, use config_file: %s", candidate)
 		}
 	}
 
-	// check if a source directory is provided
-	if len(c.SourceDirectory) > 0 {
-		// check if source directory exists
-		_, err := a.Stat(c.SourceDirectory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no source directory found @ %s", c.SourceDirectory)
-			}
+	source := c.resolve(".")
 
-			return err
+	// capture the config files in the source and config directory
+	var candidates []string
+
+	for _, name := range entries(source, false) {
+		if slices.Contains(siteConfigFormats, strings.ToLower(filepath.Ext(name))) {
+			candidates = append(candidates, name)
 		}
 	}
 
-	return nil
+	if len(c.Directory) > 0 {
+		candidates = append(candidates, siteConfigCandidates(source, c.resolve(c.Directory))...)
+	}
+
+	if match, ok := closest(file, candidates); ok {
+		return fmt.Sprintf("did you mean config_file: %s?", match)
+	}
+
+	return "create the file or update config_file"
+}
+
+// outputDirectory returns the filesystem path Hugo writes the site to.
+func (c *Config) outputDirectory() string {
+	path := c.OutputDirectory
+
+	// use the default output directory for Hugo
+	if len(path) == 0 {
+		path = "public"
+	}
+
+	return c.resolve(path)
+}
+
+// reportDirectory returns the filesystem path reports
+// are written to, which is next to the output directory.
+func (c *Config) reportDirectory() string {
+	return filepath.Dir(filepath.Clean(c.outputDirectory()))
+}
+
+// resolve returns the path relative to the source directory,
+// which mirrors how Hugo resolves relative paths.
+func (c *Config) resolve(path string) string {
+	return resolvePath(c.SourceDirectory, path)
+}
+
+// resolvePath returns the path relative to the source directory.
+//
+// Hugo resolves the config, cache, content, layout, output and themes
+// paths from the source directory, while the source directory itself
+// and the paths read by the plugin are relative to the working directory.
+func resolvePath(source, path string) string {
+	// check if the path is absolute or no source directory is provided
+	if filepath.IsAbs(path) || len(source) == 0 {
+		return path
+	}
+
+	return filepath.Join(source, path)
 }
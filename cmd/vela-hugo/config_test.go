@@ -3,7 +3,10 @@
 package main
 
 import (
+	"errors"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -44,6 +47,20 @@ func TestConfig_Validate(t *testing.T) {
 				SourceDirectory:  "/source",
 			},
 		},
+		{
+			failure: false,
+			name:    "config paths relative to source directory",
+			config: Config{
+				CacheDirectory:   "resources/_gen",
+				ContentDirectory: "content",
+				Directory:        "",
+				Environment:      "",
+				File:             "hugo.toml, hugo.staging.toml",
+				LayoutDirectory:  "layouts",
+				OutputDirectory:  "public",
+				SourceDirectory:  "/source",
+			},
+		},
 		{
 			failure: true,
 			name:    "nonexistent cache directory provided",
@@ -140,7 +157,7 @@ func TestConfig_Validate(t *testing.T) {
 			// check if the test is supposed to fail
 			if !test.failure {
 				// create the cache directory
-				err := appFS.MkdirAll(test.config.CacheDirectory, 0777)
+				err := appFS.MkdirAll(test.config.resolve(test.config.CacheDirectory), 0777)
 				if err != nil {
 					t.Errorf("unable to create cache directory %s: %v", test.config.CacheDirectory, err)
 				}
@@ -152,7 +169,7 @@ func TestConfig_Validate(t *testing.T) {
 			// check if the test is supposed to fail
 			if !test.failure {
 				// create the content directory
-				err := appFS.MkdirAll(test.config.ContentDirectory, 0777)
+				err := appFS.MkdirAll(test.config.resolve(test.config.ContentDirectory), 0777)
 				if err != nil {
 					t.Errorf("unable to create content directory %s: %v", test.config.ContentDirectory, err)
 				}
@@ -190,13 +207,15 @@ func TestConfig_Validate(t *testing.T) {
 		if len(test.config.File) > 0 {
 			// check if the test is supposed to fail
 			if !test.failure {
-				// create full path to config file in config directory
-				path := filepath.Join(test.config.Directory, test.config.File)
+				for _, file := range strings.Split(test.config.File, ",") {
+					// create full path to config file in source directory
+					path := test.config.resolve(strings.TrimSpace(file))
 
-				// create the config file
-				_, err := appFS.Create(path)
-				if err != nil {
-					t.Errorf("unable to create config file %s: %v", path, err)
+					// create the config file
+					_, err := appFS.Create(path)
+					if err != nil {
+						t.Errorf("unable to create config file %s: %v", path, err)
+					}
 				}
 			}
 		}
@@ -206,7 +225,7 @@ func TestConfig_Validate(t *testing.T) {
 			// check if the test is supposed to fail
 			if !test.failure {
 				// create the layout directory
-				err := appFS.MkdirAll(test.config.LayoutDirectory, 0777)
+				err := appFS.MkdirAll(test.config.resolve(test.config.LayoutDirectory), 0777)
 				if err != nil {
 					t.Errorf("unable to create output directory %s: %v", test.config.LayoutDirectory, err)
 				}
@@ -252,3 +271,108 @@ func TestConfig_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestConfig_reportDirectory(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{
+			name:   "default output directory",
+			config: Config{},
+			want:   ".",
+		},
+		{
+			name:   "output directory relative to source directory",
+			config: Config{OutputDirectory: "build/site", SourceDirectory: "docs"},
+			want:   "docs/build",
+		},
+		{
+			name:   "absolute output directory",
+			config: Config{OutputDirectory: "/build/", SourceDirectory: "docs"},
+			want:   "/",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.config.reportDirectory()
+
+		if got != test.want {
+			t.Errorf("%s reportDirectory is %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestConfig_fileSuggestion(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	_, err := appFS.Create("/source/config/hugo.toml")
+	if err != nil {
+		t.Errorf("unable to create config file: %v", err)
+	}
+
+	c := &Config{Directory: "config", File: "hugo.toml", SourceDirectory: "/source"}
+
+	err = c.Validate()
+	if err == nil {
+		t.Fatal("Validate should have returned err")
+	}
+
+	want := []*Problem{
+		{
+			Parameter:  "config_file",
+			Message:    "no config found @ /source/hugo.toml",
+			Suggestion: "config files are relative to the source directory, use config_file: config/hugo.toml",
+		},
+	}
+
+	var got *ValidationError
+	if !errors.As(err, &got) {
+		t.Fatalf("Validate returned %T, want *ValidationError", err)
+	}
+
+	if !reflect.DeepEqual(got.Problems, want) {
+		t.Errorf("Validate is %+v, want %+v", got.Problems, want)
+	}
+}
+
+func TestConfig_suggestion(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	for _, path := range []string{"/source/content", "/source/layouts"} {
+		err := appFS.MkdirAll(path, 0777)
+		if err != nil {
+			t.Errorf("unable to create directory %s: %v", path, err)
+		}
+	}
+
+	for _, path := range []string{"/source/hugo.toml", "/source/config/staging/hugo.yaml"} {
+		_, err := appFS.Create(path)
+		if err != nil {
+			t.Errorf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	c := &Config{Directory: "config", SourceDirectory: "/source"}
+
+	if got, want := c.suggestion("layout_directory", "layout"), "did you mean layout_directory: layouts?"; got != want {
+		t.Errorf("suggestion is %s, want %s", got, want)
+	}
+
+	if got, want := c.suggestion("cache_directory", "resources"), "create the directory or update cache_directory (relative to source_directory)"; got != want {
+		t.Errorf("suggestion is %s, want %s", got, want)
+	}
+
+	if got, want := c.fileSuggestion("hugo.tml"), "did you mean config_file: hugo.toml?"; got != want {
+		t.Errorf("fileSuggestion is %s, want %s", got, want)
+	}
+
+	if got, want := c.fileSuggestion("config/stagin/hugo.yaml"), "did you mean config_file: config/staging/hugo.yaml?"; got != want {
+		t.Errorf("fileSuggestion is %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-getter/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// name of the file the output diff is written to in JSON format.
+	_diffReportJSON = "output-diff.json"
+	// name of the file the output diff is written to in markdown format.
+	_diffReportMarkdown = "output-diff.md"
+	// pattern for the temporary directory the baseline manifest is downloaded to.
+	_baselinePattern = "vela-hugo-baseline-*"
+)
+
+// ManifestDiff represents the files which changed between two manifests.
+type ManifestDiff struct {
+	// files only in the current manifest
+	Added []*ManifestFile `json:"added"`
+	// files only in the previous manifest
+	Removed []*ManifestFile `json:"removed"`
+	// files in both manifests with different contents
+	Changed []*ManifestChange `json:"changed"`
+}
+
+// ManifestChange represents a file with different contents between two manifests.
+type ManifestChange struct {
+	// slash separated path relative to the output directory
+	Path string `json:"path"`
+	// size in bytes of the file in the previous manifest
+	PreviousSize int64 `json:"previous_size"`
+	// size in bytes of the file in the current manifest
+	Size int64 `json:"size"`
+}
+
+// readManifest reads the manifest from the filesystem path or,
+// when it doesn't exist locally, downloads it with go-getter.
+//
+// https://github.com/hashicorp/go-getter#url-format
+func readManifest(ctx context.Context, src string) (*Manifest, error) {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	path := src
+
+	// check if the manifest must be downloaded
+	if _, err := a.Stat(src); err != nil {
+		dir, err := a.TempDir("", _baselinePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err := a.RemoveAll(dir)
+			if err != nil {
+				logrus.Warnf("unable to remove baseline manifest %s: %v", dir, err)
+			}
+		}()
+
+		path = filepath.Join(dir, _manifestFile)
+
+		logrus.Infof("downloading baseline manifest from: %s", src)
+
+		_, err = getter.GetFile(ctx, path, src)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download baseline manifest %s: %w", src, err)
+		}
+	}
+
+	data, err := a.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(Manifest)
+
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse baseline manifest %s: %w", src, err)
+	}
+
+	return m, nil
+}
+
+// diffManifests compares the previous manifest to the current one.
+func diffManifests(previous, current *Manifest) *ManifestDiff {
+	d := &ManifestDiff{
+		Added:   []*ManifestFile{},
+		Removed: []*ManifestFile{},
+		Changed: []*ManifestChange{},
+	}
+
+	before := make(map[string]*ManifestFile)
+
+	for _, file := range previous.Files {
+		before[file.Path] = file
+	}
+
+	after := make(map[string]*ManifestFile)
+
+	for _, file := range current.Files {
+		after[file.Path] = file
+	}
+
+	for _, file := range current.Files {
+		old, ok := before[file.Path]
+
+		switch {
+		case !ok:
+			d.Added = append(d.Added, file)
+		case old.SHA256 != file.SHA256:
+			d.Changed = append(d.Changed, &ManifestChange{Path: file.Path, PreviousSize: old.Size, Size: file.Size})
+		}
+	}
+
+	for _, file := range previous.Files {
+		if _, ok := after[file.Path]; !ok {
+			d.Removed = append(d.Removed, file)
+		}
+	}
+
+	return d
+}
+
+// Empty returns whether no files changed between the manifests.
+func (d *ManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Write outputs the diff in JSON and markdown format to the provided directory.
+func (d *ManifestDiff) Write(dir, baseURL string) error {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// serialize the diff as pretty JSON
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, _diffReportJSON)
+
+	logrus.Infof("writing output diff to %s", path)
+
+	err = a.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	path = filepath.Join(dir, _diffReportMarkdown)
+
+	logrus.Infof("writing output diff to %s", path)
+
+	err = a.WriteFile(path, []byte(d.Markdown(baseURL)), 0644)
+	if err != nil {
+		return err
+	}
+
+	return writeOutputs(map[string]string{
+		"HUGO_OUTPUT_DIFF": path,
+	})
+}
+
+// Markdown formats the diff as a summary of the rendered pages
+// which changed, suitable for posting on a pull request.
+func (d *ManifestDiff) Markdown(baseURL string) string {
+	b := new(strings.Builder)
+
+	b.WriteString("## Rendered Page Changes\n\n")
+
+	var added, removed, changed, other []string
+
+	for _, file := range d.Added {
+		if isPage(file.Path) {
+			added = append(added, pageLink(baseURL, file.Path))
+		} else {
+			other = append(other, fmt.Sprintf("| `%s` | added | %s |", file.Path, formatSize(file.Size)))
+		}
+	}
+
+	for _, file := range d.Removed {
+		if isPage(file.Path) {
+			// removed pages no longer exist so they are not linked
+			removed = append(removed, fmt.Sprintf("`%s`", pageURL(file.Path)))
+		} else {
+			other = append(other, fmt.Sprintf("| `%s` | removed | %s |", file.Path, formatSize(file.Size)))
+		}
+	}
+
+	for _, file := range d.Changed {
+		sizes := fmt.Sprintf("%s → %s", formatSize(file.PreviousSize), formatSize(file.Size))
+
+		if isPage(file.Path) {
+			changed = append(changed, fmt.Sprintf("%s (%s)", pageLink(baseURL, file.Path), sizes))
+		} else {
+			other = append(other, fmt.Sprintf("| `%s` | changed | %s |", file.Path, sizes))
+		}
+	}
+
+	// check if any pages changed
+	if len(added)+len(removed)+len(changed) == 0 {
+		b.WriteString("No rendered pages changed compared to the previous build.\n")
+	} else {
+		fmt.Fprintf(b, "**%d** added, **%d** removed and **%d** changed page(s) compared to the previous build.\n",
+			len(added), len(removed), len(changed))
+	}
+
+	for _, section := range []struct {
+		title string
+		pages []string
+	}{
+		{title: "Added", pages: added},
+		{title: "Removed", pages: removed},
+		{title: "Changed", pages: changed},
+	} {
+		if len(section.pages) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(b, "\n### %s\n\n", section.title)
+
+		for _, page := range section.pages {
+			fmt.Fprintf(b, "- %s\n", page)
+		}
+	}
+
+	// check if any other files changed
+	if len(other) > 0 {
+		fmt.Fprintf(b, "\n<details>\n<summary>%d other file(s) changed</summary>\n\n", len(other))
+		b.WriteString("| File | Change | Size |\n")
+		b.WriteString("| ---- | ------ | ---- |\n")
+
+		for _, row := range other {
+			fmt.Fprintf(b, "%s\n", row)
+		}
+
+		b.WriteString("\n</details>\n")
+	}
+
+	return b.String()
+}
+
+// isPage returns whether the file in the output directory is a rendered page.
+func isPage(file string) bool {
+	return strings.EqualFold(path.Ext(file), ".html")
+}
+
+// pageURL returns the URL path the rendered page is served from.
+func pageURL(file string) string {
+	if path.Base(file) == "index.html" {
+		dir := path.Dir(file)
+		if dir == "." {
+			return "/"
+		}
+
+		return "/" + dir + "/"
+	}
+
+	return "/" + file
+}
+
+// pageLink returns the markdown link to the rendered page on the site.
+func pageLink(baseURL, file string) string {
+	url := pageURL(file)
+
+	// check if the page can be linked
+	if len(baseURL) == 0 {
+		return fmt.Sprintf("`%s`", url)
+	}
+
+	return fmt.Sprintf("[`%s`](%s%s)", url, strings.TrimSuffix(baseURL, "/"), url)
+}
+
+// formatSize returns the size in bytes in a human readable format.
+func formatSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestDiffManifests(t *testing.T) {
+	// setup types
+	previous := &Manifest{
+		Files: []*ManifestFile{
+			{Path: "css/main.css", Size: 7, SHA256: "a"},
+			{Path: "docs/index.html", Size: 10, SHA256: "b"},
+			{Path: "old/index.html", Size: 5, SHA256: "c"},
+		},
+	}
+
+	current := &Manifest{
+		Files: []*ManifestFile{
+			{Path: "css/main.css", Size: 7, SHA256: "a"},
+			{Path: "docs/index.html", Size: 12, SHA256: "d"},
+			{Path: "new/index.html", Size: 8, SHA256: "e"},
+		},
+	}
+
+	want := &ManifestDiff{
+		Added:   []*ManifestFile{current.Files[2]},
+		Removed: []*ManifestFile{previous.Files[2]},
+		Changed: []*ManifestChange{{Path: "docs/index.html", PreviousSize: 10, Size: 12}},
+	}
+
+	got := diffManifests(previous, current)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffManifests is %+v, want %+v", got, want)
+	}
+
+	if !diffManifests(current, current).Empty() {
+		t.Errorf("diffManifests of the same manifest should be empty")
+	}
+}
+
+func TestManifestDiff_Markdown(t *testing.T) {
+	// setup types
+	d := &ManifestDiff{
+		Added:   []*ManifestFile{{Path: "docs/new/index.html", Size: 2048}},
+		Removed: []*ManifestFile{{Path: "404.html", Size: 100}},
+		Changed: []*ManifestChange{
+			{Path: "index.html", PreviousSize: 1024, Size: 1536},
+			{Path: "css/main.css", PreviousSize: 10, Size: 12},
+		},
+	}
+
+	want := `## Rendered Page Changes
+
+**1** added, **1** removed and **1** changed page(s) compared to the previous build.
+
+### Added
+
+- [` + "`/docs/new/`" + `](https://docs.example.com/docs/new/)
+
+### Removed
+
+- ` + "`/404.html`" + `
+
+### Changed
+
+- [` + "`/`" + `](https://docs.example.com/) (1.0 KB → 1.5 KB)
+
+<details>
+<summary>1 other file(s) changed</summary>
+
+| File | Change | Size |
+| ---- | ------ | ---- |
+| ` + "`css/main.css`" + ` | changed | 10 B → 12 B |
+
+</details>
+`
+
+	got := d.Markdown("https://docs.example.com/")
+
+	if got != want {
+		t.Errorf("Markdown is\n%s\nwant\n%s", got, want)
+	}
+
+	got = new(ManifestDiff).Markdown("")
+
+	if !strings.Contains(got, "No rendered pages changed") {
+		t.Errorf("Markdown is %s, want no rendered pages changed", got)
+	}
+}
+
+func TestPageLink(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		baseURL string
+		file    string
+		want    string
+	}{
+		{baseURL: "", file: "index.html", want: "`/`"},
+		{baseURL: "", file: "docs/index.html", want: "`/docs/`"},
+		{baseURL: "", file: "404.html", want: "`/404.html`"},
+		{baseURL: "https://docs.example.com", file: "docs/index.html", want: "[`/docs/`](https://docs.example.com/docs/)"},
+		{baseURL: "https://example.com/docs/", file: "guide/index.html", want: "[`/guide/`](https://example.com/docs/guide/)"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := pageLink(test.baseURL, test.file)
+
+		if got != test.want {
+			t.Errorf("pageLink for %s is %s, want %s", test.file, got, test.want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KB"},
+		{size: 200 * 1024, want: "200.0 KB"},
+		{size: 5 * 1024 * 1024, want: "5.0 MB"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := formatSize(test.size)
+
+		if got != test.want {
+			t.Errorf("formatSize for %d is %s, want %s", test.size, got, test.want)
+		}
+	}
+}
+
+func TestReadManifest_URL(t *testing.T) {
+	// go-getter downloads to the os file system
+	appFS = afero.NewOsFs()
+
+	t.Cleanup(func() { appFS = afero.NewMemMapFs() })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"count": 1, "size": 4, "files": [{"path": "index.html", "size": 4, "sha256": "a"}]}`))
+	}))
+	defer server.Close()
+
+	got, err := readManifest(t.Context(), server.URL+"/manifest.json")
+	if err != nil {
+		t.Fatalf("readManifest returned err: %v", err)
+	}
+
+	if got.Count != 1 || got.Files[0].Path != "index.html" {
+		t.Errorf("readManifest is %+v, want index.html", got)
+	}
+
+	_, err = readManifest(t.Context(), filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil {
+		t.Errorf("readManifest should have returned err for a missing manifest")
+	}
+}
+
+func TestOutput_Exec_Baseline(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	t.Setenv("VELA_OUTPUTS", "/vela/outputs.env")
+
+	err := a.WriteFile("/baseline.json", []byte(`{"files": [{"path": "index.html", "size": 5, "sha256": "a"}]}`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create baseline: %v", err)
+	}
+
+	// setup types
+	o := &Output{Baseline: "/baseline.json"}
+
+	err = o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	data, err := a.ReadFile("/site/output-diff.md")
+	if err != nil {
+		t.Fatalf("unable to read output diff: %v", err)
+	}
+
+	if !strings.Contains(string(data), "**1** added, **0** removed and **1** changed page(s)") {
+		t.Errorf("output diff is %s, want 1 added and 1 changed page", data)
+	}
+
+	_, err = a.Stat("/site/output-diff.json")
+	if err != nil {
+		t.Errorf("output diff should have been written in JSON format: %v", err)
+	}
+
+	outputs, err := a.ReadFile("/vela/outputs.env")
+	if err != nil {
+		t.Fatalf("unable to read step outputs: %v", err)
+	}
+
+	if string(outputs) != "HUGO_OUTPUT_DIFF=/site/output-diff.md\n" {
+		t.Errorf("step outputs are %s, want HUGO_OUTPUT_DIFF", outputs)
+	}
+
+	_, err = a.Stat("/site/manifest.json")
+	if err == nil {
+		t.Errorf("manifest should not have been written")
+	}
+}
+
+func TestOutput_Exec_BaselineUnchanged(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	t.Setenv("VELA_OUTPUTS", "/vela/outputs.env")
+
+	manifest, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("unable to build manifest: %v", err)
+	}
+
+	err = manifest.Write("/baseline.json")
+	if err != nil {
+		t.Fatalf("unable to create baseline: %v", err)
+	}
+
+	// setup types
+	o := &Output{Baseline: "/baseline.json"}
+
+	err = o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	data, err := a.ReadFile("/site/output-diff.md")
+	if err != nil {
+		t.Fatalf("unable to read output diff: %v", err)
+	}
+
+	if !strings.Contains(string(data), "No rendered pages changed compared to the previous build.") {
+		t.Errorf("output diff is %s, want no changes summary", data)
+	}
+
+	_, err = a.Stat("/site/output-diff.json")
+	if err != nil {
+		t.Errorf("output diff should have been written in JSON format: %v", err)
+	}
+
+	outputs, err := a.ReadFile("/vela/outputs.env")
+	if err != nil {
+		t.Fatalf("unable to read step outputs: %v", err)
+	}
+
+	if string(outputs) != "HUGO_OUTPUT_DIFF=/site/output-diff.md\n" {
+		t.Errorf("step outputs are %s, want HUGO_OUTPUT_DIFF", outputs)
+	}
+}
@@ -0,0 +1,421 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+)
+
+// name of the file git records the submodules of a repository in.
+const _gitModules = ".gitmodules"
+
+// Diagnosis represents the findings from inspecting the workspace for a site.
+type Diagnosis struct {
+	// source directory of the site, relative to the workspace
+	Root string
+	// config files read for the site
+	ConfigFiles []string
+	// formats of the config files read for the site
+	ConfigFormats []string
+	// themes used by the site
+	Themes []string
+	// hugo modules imported by the site
+	Imports []string
+	// whether the site is a hugo module with a go.mod
+	Module bool
+	// minimum hugo version required by the site or its themes
+	MinVersion string
+	// whether the extended binary is required
+	Extended bool
+	// reason the extended binary is required
+	ExtendedReason string
+	// whether the site uses Dart Sass
+	DartSass bool
+	// whether the site has npm packages
+	NPM bool
+	// npm tooling used by the site, e.g. postcss
+	NPMTools []string
+	// git submodules of the workspace
+	Submodules []*Submodule
+	// notes about the workspace which need attention
+	Notes []string
+}
+
+// Submodule represents a git submodule of the workspace.
+type Submodule struct {
+	// filesystem path to the submodule
+	Path string
+	// whether the submodule has been checked out
+	Initialized bool
+}
+
+// doctor inspects the workspace without building the
+// site and prints the recommended plugin parameters.
+func doctor(_ context.Context, c *cli.Command) error {
+	setLogLevel(c.String("log.level"))
+
+	source := c.String("config.source_directory")
+
+	// detect the site root when no source directory is provided
+	if len(source) == 0 {
+		root, ok := findSiteRoot(".", c.String("config.directory"))
+		if !ok {
+			return fmt.Errorf("no hugo site found in the workspace or its subdirectories")
+		}
+
+		source = root
+	}
+
+	d, err := diagnose(&Config{
+		Directory:       c.String("config.directory"),
+		Environment:     c.String("config.environment"),
+		File:            c.String("config.file"),
+		SourceDirectory: source,
+	}, c.String("theme.directory"))
+	if err != nil {
+		return err
+	}
+
+	d.Print(os.Stdout)
+
+	return nil
+}
+
+// findSiteRoot returns the directory of the site, which is
+// the directory or the first subdirectory with a site config.
+func findSiteRoot(dir, configDirectory string) (string, bool) {
+	candidates := []string{dir}
+
+	for _, name := range entries(dir, true) {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+
+	for _, candidate := range candidates {
+		c := &Config{Directory: configDirectory, SourceDirectory: candidate}
+
+		name, err := c.defaultSiteFile()
+		if err == nil && len(name) > 0 {
+			return candidate, true
+		}
+
+		// check for a config directory without a root config file
+		exists, err := afero.DirExists(appFS, c.resolve(filepath.Join(configDirectory, _defaultEnvironment)))
+		if err == nil && exists {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// diagnose inspects the site for the configuration it needs to build.
+func diagnose(c *Config, themeDirectory string) (*Diagnosis, error) {
+	logrus.Debugf("diagnosing site @ %s", c.resolve("."))
+
+	d := &Diagnosis{Root: c.resolve(".")}
+
+	site, err := c.Site()
+	if err != nil {
+		return nil, err
+	}
+
+	d.ConfigFiles = site.Files
+
+	for _, file := range site.Files {
+		format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+		if !slices.Contains(d.ConfigFormats, format) {
+			d.ConfigFormats = append(d.ConfigFormats, format)
+		}
+	}
+
+	d.Themes = site.Themes
+	d.Imports = site.Imports
+
+	// check if the site is a hugo module
+	d.Module, _ = afero.Exists(appFS, c.resolve("go.mod"))
+
+	// capture the hugo version required by the site
+	if site.HugoVersion != nil {
+		d.MinVersion = site.HugoVersion.Min
+
+		if site.HugoVersion.Extended {
+			d.Extended, d.ExtendedReason = true, "module.hugoVersion.extended"
+		}
+	}
+
+	// inspect the themes installed in the theme directory
+	dirs := []string{d.Root}
+
+	for _, theme := range d.Themes {
+		// themes with a path are imported as hugo modules
+		if strings.Contains(theme, "/") {
+			continue
+		}
+
+		path := filepath.Join(c.resolve(themeDirectory), theme)
+
+		exists, _ := afero.DirExists(appFS, path)
+		if !exists {
+			d.Notes = append(d.Notes, fmt.Sprintf("theme %s is not installed @ %s", theme, path))
+
+			continue
+		}
+
+		dirs = append(dirs, path)
+
+		d.MinVersion = maxVersion(d.MinVersion, themeMinVersion(path))
+	}
+
+	// inspect the assets and layouts for the css tooling
+	var sass []string
+
+	for _, dir := range dirs {
+		if d.inspectStyles(dir) {
+			sass = append(sass, dir)
+		}
+	}
+
+	// LibSass is only included in the extended binary
+	if len(sass) > 0 && !d.DartSass && !d.Extended {
+		d.Extended, d.ExtendedReason = true, fmt.Sprintf("Sass files in %s", strings.Join(sass, ", "))
+	}
+
+	// inspect the npm packages for the css tooling
+	d.inspectPackages(d.Root)
+
+	// inspect the git submodules of the workspace
+	d.Submodules = readSubmodules(".")
+
+	for _, submodule := range d.Submodules {
+		if !submodule.Initialized {
+			d.Notes = append(d.Notes, fmt.Sprintf("git submodule %s is not initialized, enable submodules for the clone step", submodule.Path))
+		}
+	}
+
+	return d, nil
+}
+
+// inspectStyles detects Dart Sass in the layouts of the directory
+// and returns whether the directory has any Sass files.
+func (d *Diagnosis) inspectStyles(dir string) bool {
+	var sass bool
+
+	for _, sub := range []string{"assets", "layouts"} {
+		_ = afero.Walk(appFS, filepath.Join(dir, sub), func(path string, info fs.FileInfo, err error) error {
+			// skip entries which can't be read
+			if err != nil || info.IsDir() {
+				return nil
+			}
+
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".scss", ".sass":
+				sass = true
+			case ".html":
+				data, err := afero.ReadFile(appFS, path)
+				if err == nil && bytes.Contains(data, []byte("dartsass")) {
+					d.DartSass = true
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return sass
+}
+
+// inspectPackages detects the npm tooling used by the site.
+func (d *Diagnosis) inspectPackages(dir string) {
+	// check if the site has a package manifest
+	d.NPM, _ = afero.Exists(appFS, filepath.Join(dir, _packageJSON))
+	if !d.NPM {
+		return
+	}
+
+	packages, err := readPackages(dir)
+	if err != nil {
+		d.Notes = append(d.Notes, fmt.Sprintf("unable to read npm packages: %v", err))
+
+		return
+	}
+
+	for _, tool := range []string{"postcss", "tailwindcss", "autoprefixer", "sass"} {
+		for _, pkg := range packages {
+			if pkg.Name == tool || (pkg.Name == "@tailwindcss/cli" && tool == "tailwindcss") {
+				d.NPMTools = append(d.NPMTools, tool)
+
+				break
+			}
+		}
+	}
+}
+
+// themeMinVersion returns the minimum hugo version from the theme configuration.
+func themeMinVersion(dir string) string {
+	data, err := afero.ReadFile(appFS, filepath.Join(dir, "theme.toml"))
+	if err != nil {
+		return ""
+	}
+
+	meta := struct {
+		MinVersion any `toml:"min_version"`
+	}{}
+
+	err = toml.Unmarshal(data, &meta)
+	if err != nil || meta.MinVersion == nil {
+		return ""
+	}
+
+	return fmt.Sprint(meta.MinVersion)
+}
+
+// maxVersion returns the greater of the versions, ignoring invalid versions.
+func maxVersion(a, b string) string {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+
+	switch {
+	case errB != nil:
+		return a
+	case errA != nil || vb.GreaterThan(va):
+		return b
+	default:
+		return a
+	}
+}
+
+// readSubmodules returns the git submodules recorded in the directory.
+func readSubmodules(dir string) []*Submodule {
+	data, err := afero.ReadFile(appFS, filepath.Join(dir, _gitModules))
+	if err != nil {
+		return nil
+	}
+
+	var submodules []*Submodule
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.TrimSpace(key) != "path" {
+			continue
+		}
+
+		path := filepath.Join(dir, strings.TrimSpace(value))
+
+		// a submodule which hasn't been checked out is an empty directory
+		submodules = append(submodules, &Submodule{
+			Path:        path,
+			Initialized: len(entries(path, true))+len(entries(path, false)) > 0,
+		})
+	}
+
+	return submodules
+}
+
+// Parameters returns the recommended plugin parameters for the site.
+func (d *Diagnosis) Parameters() [][2]string {
+	var params [][2]string
+
+	// check if the site is in a subdirectory of the workspace
+	if filepath.Clean(d.Root) != "." {
+		params = append(params, [2]string{"source_directory", d.Root})
+	}
+
+	if len(d.MinVersion) > 0 {
+		params = append(params, [2]string{"version", strings.TrimPrefix(d.MinVersion, "v")})
+	}
+
+	if d.Extended {
+		params = append(params, [2]string{"extended", "true"})
+	}
+
+	if d.NPM {
+		params = append(params, [2]string{"npm_install", "true"})
+	}
+
+	if d.Module || len(d.Imports) > 0 {
+		params = append(params, [2]string{"module_report", "true"})
+	}
+
+	return params
+}
+
+// Print writes the findings and recommended parameters for the site.
+func (d *Diagnosis) Print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "site root:\t%s\n", d.Root)
+	fmt.Fprintf(w, "config:\t%s (%s)\n", strings.Join(d.ConfigFiles, ", "), strings.Join(d.ConfigFormats, ", "))
+	fmt.Fprintf(w, "themes:\t%s\n", orNone(strings.Join(d.Themes, ", ")))
+	fmt.Fprintf(w, "hugo modules:\t%s\n", orNone(strings.Join(d.Imports, ", ")))
+
+	version := orNone(d.MinVersion)
+	if d.Extended {
+		version = fmt.Sprintf("%s (extended: %s)", version, d.ExtendedReason)
+	}
+
+	fmt.Fprintf(w, "hugo version:\t%s\n", version)
+	fmt.Fprintf(w, "dart sass:\t%t\n", d.DartSass)
+
+	packages := "-"
+	if d.NPM {
+		packages = fmt.Sprintf("%s (%s)", _packageJSON, orNone(strings.Join(d.NPMTools, ", ")))
+	}
+
+	fmt.Fprintf(w, "npm packages:\t%s\n", packages)
+
+	var submodules []string
+
+	for _, submodule := range d.Submodules {
+		state := "initialized"
+		if !submodule.Initialized {
+			state = "not initialized"
+		}
+
+		submodules = append(submodules, fmt.Sprintf("%s (%s)", submodule.Path, state))
+	}
+
+	fmt.Fprintf(w, "git submodules:\t%s\n", orNone(strings.Join(submodules, ", ")))
+
+	w.Flush()
+
+	for _, note := range d.Notes {
+		fmt.Fprintf(out, "\nNOTE: %s", note)
+	}
+
+	if d.DartSass {
+		fmt.Fprint(out, "\nNOTE: the site uses Dart Sass, which must be installed in the image")
+	}
+
+	if len(d.Notes) > 0 || d.DartSass {
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprint(out, "\nrecommended parameters:\n\n  parameters:\n")
+
+	params := d.Parameters()
+	if len(params) == 0 {
+		fmt.Fprintln(out, "    # the defaults are sufficient for the site")
+	}
+
+	for _, param := range params {
+		fmt.Fprintf(out, "    %s: %s\n", param[0], param[1])
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_diagnose(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	files := map[string]string{
+		".gitmodules": `[submodule "docs/themes/docsy"]
+	path = docs/themes/docsy
+	url = https://github.com/google/docsy.git
+[submodule "docs/themes/extras"]
+	path = docs/themes/extras
+	url = https://github.com/example/extras.git`,
+		"docs/hugo.toml": `baseURL = "https://example.com/"
+theme = ["docsy", "extras"]
+
+[module.hugoVersion]
+min = "0.110.0"`,
+		"docs/package.json":                           `{"devDependencies": {"postcss": "^8.4.0", "postcss-cli": "^11.0.0", "autoprefixer": "^10.4.0"}}`,
+		"docs/assets/scss/main.scss":                  `body { color: red; }`,
+		"docs/themes/docsy/theme.toml":                `min_version = "0.120.0"`,
+		"docs/themes/docsy/layouts/partials/css.html": `{{ $opts := dict "transpiler" "dartsass" }}`,
+	}
+
+	for path, content := range files {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Errorf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	err := a.MkdirAll("docs/themes/extras", 0777)
+	if err != nil {
+		t.Errorf("unable to create directory: %v", err)
+	}
+
+	root, ok := findSiteRoot(".", "config")
+	if !ok || root != "docs" {
+		t.Errorf("findSiteRoot is %s (%t), want docs", root, ok)
+	}
+
+	got, err := diagnose(&Config{Directory: "config", SourceDirectory: root}, "themes")
+	if err != nil {
+		t.Fatalf("diagnose returned err: %v", err)
+	}
+
+	want := [][2]string{
+		{"source_directory", "docs"},
+		{"version", "0.120.0"},
+		{"npm_install", "true"},
+	}
+
+	if !reflect.DeepEqual(got.Parameters(), want) {
+		t.Errorf("diagnose parameters are %v, want %v", got.Parameters(), want)
+	}
+
+	if !got.DartSass || got.Extended {
+		t.Errorf("diagnose should detect Dart Sass without the extended binary")
+	}
+
+	if !reflect.DeepEqual(got.NPMTools, []string{"postcss", "autoprefixer"}) {
+		t.Errorf("diagnose npm tools are %v, want [postcss autoprefixer]", got.NPMTools)
+	}
+
+	wantSubmodules := []*Submodule{
+		{Path: "docs/themes/docsy", Initialized: true},
+		{Path: "docs/themes/extras", Initialized: false},
+	}
+
+	if !reflect.DeepEqual(got.Submodules, wantSubmodules) {
+		t.Errorf("diagnose submodules are %+v, want %+v", got.Submodules, wantSubmodules)
+	}
+}
+
+func Test_findSiteRoot_ConfigDirectory(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	// the site only has a custom config directory without a root config file
+	err := a.WriteFile("site/settings/_default/hugo.toml", []byte(`title = "Docs"`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		directory string
+		want      string
+		found     bool
+	}{
+		{directory: "settings", want: "site", found: true},
+		{directory: "config", want: "", found: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, ok := findSiteRoot(".", test.directory)
+
+		if got != test.want || ok != test.found {
+			t.Errorf("findSiteRoot with %s is %s (%t), want %s (%t)", test.directory, got, ok, test.want, test.found)
+		}
+	}
+}
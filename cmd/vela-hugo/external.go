@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// name of the file the external link report is written to.
+const _externalReportFile = "external-links.json"
+
+// number of workers requesting external links at the same time.
+const _externalWorkers = 16
+
+// maximum requests per second when checking external links.
+const _maxExternalRate = 1000
+
+// maximum delay honored from the Retry-After header for external links.
+const _maxRetryAfter = 30 * time.Second
+
+// externalBackoff is the delay before the first retry of an external
+// link, which doubles for each retry and is overridden by tests.
+var externalBackoff = time.Second
+
+// ExternalLink represents the status of a link to another site.
+type ExternalLink struct {
+	// url the link refers to
+	URL string `json:"url"`
+	// http status code returned for the url
+	Status int `json:"status,omitempty"`
+	// error returned when requesting the url
+	Error string `json:"error,omitempty"`
+	// pages and lines the url is linked from
+	Sources []string `json:"sources"`
+}
+
+// Broken returns whether the link didn't resolve successfully.
+func (l *ExternalLink) Broken() bool {
+	return len(l.Error) > 0 || l.Status >= http.StatusBadRequest
+}
+
+// linkChecker requests external links with a rate limit,
+// bounded concurrency per host and retries with backoff.
+type linkChecker struct {
+	client  *http.Client
+	limit   <-chan time.Time
+	workers int
+	perHost int
+	retries int
+	// maximum delay honored from the Retry-After header
+	maxWait time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// externalPatterns compiles the include and ignore patterns for external links.
+func (o *Output) externalPatterns() ([]*regexp.Regexp, []*regexp.Regexp, error) {
+	v := new(ValidationError)
+
+	compile := func(parameter string, patterns []string) []*regexp.Regexp {
+		var compiled []*regexp.Regexp
+
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				v.add(parameter, "provide a regular expression, e.g. ^https://github\\.com/", "invalid pattern %q: %v", pattern, err)
+
+				continue
+			}
+
+			compiled = append(compiled, re)
+		}
+
+		return compiled
+	}
+
+	include := compile("external_links_include", o.ExternalInclude)
+	ignore := compile("external_links_ignore", o.ExternalIgnore)
+
+	return include, ignore, v.err()
+}
+
+// validateExternal captures the problems with the external link configuration.
+func (o *Output) validateExternal(v *ValidationError) {
+	// check if external links should be checked
+	if !o.CheckExternalLinks {
+		return
+	}
+
+	_, _, err := o.externalPatterns()
+	v.merge(err)
+
+	if o.ExternalRate < 1 || o.ExternalRate > _maxExternalRate {
+		v.add("external_links_rate", fmt.Sprintf("use 1 to %d requests per second", _maxExternalRate), "invalid rate provided: %d", o.ExternalRate)
+	}
+
+	if o.ExternalPerHost < 1 {
+		v.add("external_links_per_host", "use at least 1 request per host", "invalid concurrency provided: %d", o.ExternalPerHost)
+	}
+
+	if o.ExternalRetries < 0 {
+		v.add("external_links_retries", "use 0 to disable retries", "invalid retries provided: %d", o.ExternalRetries)
+	}
+
+	_, err = time.ParseDuration(o.ExternalTimeout)
+	if err != nil {
+		v.add("external_links_timeout", "use a duration like 10s", "invalid timeout provided: %s", o.ExternalTimeout)
+	}
+}
+
+// collectExternalLinks returns the links to other sites from the
+// rendered pages, which match the include and ignore patterns.
+func collectExternalLinks(output, baseURL string, m *Manifest, include, ignore []*regexp.Regexp) ([]*ExternalLink, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse base url %s: %w", baseURL, err)
+	}
+
+	pages, err := readPages(output, m)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make(map[string]*ExternalLink)
+
+	for _, path := range sortedKeys(pages) {
+		for _, link := range pages[path].Links {
+			ref, err := url.Parse(link.URL)
+			if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") || strings.EqualFold(ref.Host, base.Host) {
+				continue
+			}
+
+			// the fragment isn't sent with the request
+			ref.Fragment = ""
+			target := ref.String()
+
+			if !matchesAny(include, target, true) || matchesAny(ignore, target, false) {
+				continue
+			}
+
+			if _, ok := links[target]; !ok {
+				links[target] = &ExternalLink{URL: target}
+			}
+
+			links[target].Sources = append(links[target].Sources, fmt.Sprintf("%s:%d", path, link.Line))
+		}
+	}
+
+	var result []*ExternalLink
+
+	for _, target := range sortedKeys(links) {
+		result = append(result, links[target])
+	}
+
+	return result, nil
+}
+
+// matchesAny returns whether the value matches any of the patterns,
+// which returns the fallback when no patterns are provided.
+func matchesAny(patterns []*regexp.Regexp, value string, fallback bool) bool {
+	if len(patterns) == 0 {
+		return fallback
+	}
+
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAll requests every link with a pool of workers, where the concurrency
+// is also bounded per host, and captures the status in the provided links.
+func (c *linkChecker) checkAll(ctx context.Context, links []*ExternalLink) {
+	queue := make(chan *ExternalLink)
+
+	var wg sync.WaitGroup
+
+	for range max(c.workers, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for link := range queue {
+				c.check(ctx, link)
+			}
+		}()
+	}
+
+	for _, link := range links {
+		queue <- link
+	}
+
+	close(queue)
+	wg.Wait()
+}
+
+// host returns the semaphore bounding the concurrent requests to the host.
+func (c *linkChecker) host(name string) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.hosts[name]; !ok {
+		c.hosts[name] = make(chan struct{}, c.perHost)
+	}
+
+	return c.hosts[name]
+}
+
+// check requests the link, retrying with backoff for
+// network errors, rate limiting and server errors.
+func (c *linkChecker) check(ctx context.Context, link *ExternalLink) {
+	u, err := url.Parse(link.URL)
+	if err != nil {
+		link.Error = err.Error()
+
+		return
+	}
+
+	sem := c.host(u.Host)
+
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	for attempt := 0; ; attempt++ {
+		status, wait, err := c.request(ctx, link.URL)
+
+		link.Status, link.Error = status, ""
+		if err != nil {
+			link.Error = err.Error()
+		}
+
+		// check if the request should be retried
+		retry := err != nil || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+		if !retry || attempt >= c.retries || ctx.Err() != nil {
+			return
+		}
+
+		// honor the delay requested by the server if it's longer
+		backoff := externalBackoff << attempt
+		if wait > backoff {
+			backoff = wait
+		}
+
+		logrus.Debugf("retrying %s in %s", link.URL, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// request sends a HEAD request for the url, which falls back to a GET
+// request for servers which don't support it, and returns the status and
+// the delay requested by the server before retrying.
+func (c *linkChecker) request(ctx context.Context, target string) (int, time.Duration, error) {
+	var resp *http.Response
+
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		// wait for the rate limit before sending the request
+		select {
+		case <-ctx.Done():
+			return 0, 0, ctx.Err()
+		case <-c.limit:
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		req.Header.Set("User-Agent", "vela-hugo link checker")
+
+		resp, err = c.client.Do(req)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		resp.Body.Close()
+
+		// check if the server supports HEAD requests
+		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+
+	return resp.StatusCode, min(retryAfter(resp.Header.Get("Retry-After"), time.Now()), c.maxWait), nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header,
+// which is either a number of seconds or an http date.
+//
+// https://www.rfc-editor.org/rfc/rfc9110#field.retry-after
+func retryAfter(value string, now time.Time) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	date, err := http.ParseTime(value)
+	if err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// writeExternalLinks outputs the status of every external link to the provided path.
+func writeExternalLinks(path string, links []*ExternalLink) error {
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("writing external link report to %s", path)
+
+	return afero.WriteFile(appFS, path, append(data, '\n'), 0644)
+}
+
+// printExternalLinks prints the status of every external link as a table.
+func printExternalLinks(links []*ExternalLink) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "  STATUS\tURL\tSOURCE")
+
+	for _, link := range links {
+		status := strconv.Itoa(link.Status)
+		if len(link.Error) > 0 {
+			status = link.Error
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", status, link.URL, link.Sources[0])
+	}
+
+	w.Flush()
+}
+
+// checkExternalLinks verifies the links to other sites from the rendered pages.
+func (o *Output) checkExternalLinks(ctx context.Context, output, dir, baseURL string, m *Manifest) error {
+	include, ignore, err := o.externalPatterns()
+	if err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(o.ExternalTimeout)
+	if err != nil {
+		return err
+	}
+
+	links, err := collectExternalLinks(output, baseURL, m, include, ignore)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("checking %d external link(s)", len(links))
+
+	ticker := time.NewTicker(time.Second / time.Duration(o.ExternalRate))
+	defer ticker.Stop()
+
+	c := &linkChecker{
+		client:  &http.Client{Timeout: timeout},
+		limit:   ticker.C,
+		workers: _externalWorkers,
+		perHost: o.ExternalPerHost,
+		retries: o.ExternalRetries,
+		maxWait: min(timeout, _maxRetryAfter),
+		hosts:   make(map[string]chan struct{}),
+	}
+
+	c.checkAll(ctx, links)
+
+	printExternalLinks(links)
+
+	err = writeExternalLinks(filepath.Join(dir, _externalReportFile), links)
+	if err != nil {
+		return err
+	}
+
+	broken := 0
+
+	for _, link := range links {
+		if link.Broken() {
+			broken++
+		}
+	}
+
+	if broken > 0 {
+		return fmt.Errorf("output contains broken external links: %d broken link(s) found", broken)
+	}
+
+	return nil
+}
@@ -0,0 +1,457 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectExternalLinks(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	err := a.WriteFile("/site/public/index.html", []byte(`<html>
+<a href="https://github.com/go-vela/vela-hugo#readme">GitHub</a>
+<a href="https://docs.example.com/docs/">Docs</a>
+<a href="https://twitter.com/govela">Twitter</a>
+<a href="mailto:docs@example.com">Email</a>
+<img src="https://cdn.example.com/logo.png">
+</html>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = a.WriteFile("/site/public/docs/index.html", []byte(`<a href="https://github.com/go-vela/vela-hugo">GitHub</a>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		include []*regexp.Regexp
+		ignore  []*regexp.Regexp
+		want    []*ExternalLink
+	}{
+		{
+			name: "every external link",
+			want: []*ExternalLink{
+				{URL: "https://cdn.example.com/logo.png", Sources: []string{"index.html:6"}},
+				{URL: "https://github.com/go-vela/vela-hugo", Sources: []string{"docs/index.html:1", "index.html:2"}},
+				{URL: "https://twitter.com/govela", Sources: []string{"index.html:4"}},
+			},
+		},
+		{
+			name:    "included and ignored links",
+			include: []*regexp.Regexp{regexp.MustCompile(`^https://(github|twitter)\.com/`)},
+			ignore:  []*regexp.Regexp{regexp.MustCompile(`twitter\.com`)},
+			want: []*ExternalLink{
+				{URL: "https://github.com/go-vela/vela-hugo", Sources: []string{"docs/index.html:1", "index.html:2"}},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := collectExternalLinks("/site/public", "https://docs.example.com/", m, test.include, test.ignore)
+		if err != nil {
+			t.Errorf("%s collectExternalLinks returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s collectExternalLinks is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLinkChecker_checkAll(t *testing.T) {
+	// retry without waiting
+	externalBackoff = time.Millisecond
+
+	t.Cleanup(func() { externalBackoff = time.Second })
+
+	var (
+		mu       sync.Mutex
+		attempts = make(map[string]int)
+		active   atomic.Int32
+		peak     atomic.Int32
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// track the concurrent requests to the host
+		current := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		attempts[r.URL.Path]++
+		attempt := attempts[r.URL.Path]
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/flaky":
+			// fail the first request which must be retried, asking for a
+			// delay longer than the maximum the checker honors
+			if attempt == 1 {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		case "/down":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	limit := make(chan time.Time)
+	close(limit)
+
+	c := &linkChecker{
+		client:  server.Client(),
+		limit:   limit,
+		workers: 4,
+		perHost: 2,
+		retries: 2,
+		maxWait: time.Millisecond,
+		hosts:   make(map[string]chan struct{}),
+	}
+
+	links := []*ExternalLink{
+		{URL: server.URL + "/ok"},
+		{URL: server.URL + "/missing"},
+		{URL: server.URL + "/flaky"},
+		{URL: server.URL + "/get-only"},
+		{URL: server.URL + "/down"},
+	}
+
+	c.checkAll(t.Context(), links)
+
+	want := map[string]int{
+		"/ok":       http.StatusOK,
+		"/missing":  http.StatusNotFound,
+		"/flaky":    http.StatusOK,
+		"/get-only": http.StatusOK,
+		"/down":     http.StatusBadGateway,
+	}
+
+	for _, link := range links {
+		path := link.URL[len(server.URL):]
+
+		if link.Status != want[path] {
+			t.Errorf("status for %s is %d (%s), want %d", path, link.Status, link.Error, want[path])
+		}
+	}
+
+	if attempts["/down"] != 3 {
+		t.Errorf("requests for /down are %d, want 3", attempts["/down"])
+	}
+
+	if attempts["/missing"] != 1 {
+		t.Errorf("requests for /missing are %d, want 1", attempts["/missing"])
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("concurrent requests to the host are %d, want at most 2", peak.Load())
+	}
+}
+
+func TestLinkChecker_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	c := &linkChecker{
+		client:  server.Client(),
+		limit:   ticker.C,
+		workers: 5,
+		perHost: 5,
+		hosts:   make(map[string]chan struct{}),
+	}
+
+	var links []*ExternalLink
+
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/e"} {
+		links = append(links, &ExternalLink{URL: server.URL + path})
+	}
+
+	start := time.Now()
+
+	c.checkAll(t.Context(), links)
+
+	// every request waits for the next tick of the rate limit
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("checkAll took %s, want at least 80ms for the rate limit", elapsed)
+	}
+}
+
+func TestOutput_Exec_ExternalLinks(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// setup types
+	o := &Output{
+		CheckExternalLinks: true,
+		ExternalPerHost:    2,
+		ExternalRate:       100,
+		ExternalTimeout:    "5s",
+	}
+
+	err := a.WriteFile("/site/public/index.html", []byte(`<a href="`+server.URL+`/ok">OK</a>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	data, err := a.ReadFile("/site/external-links.json")
+	if err != nil {
+		t.Fatalf("unable to read external link report: %v", err)
+	}
+
+	var got []*ExternalLink
+
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("unable to parse external link report: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Status != http.StatusOK {
+		t.Errorf("external link report is %s, want 200 for /ok", data)
+	}
+
+	err = a.WriteFile("/site/public/index.html", []byte(`<a href="`+server.URL+`/missing">Missing</a>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestOutput_Validate_External(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		output Output
+		want   []string
+	}{
+		{
+			output: Output{ExternalRate: 0},
+		},
+		{
+			output: Output{CheckExternalLinks: true, ExternalPerHost: 2, ExternalRate: 10, ExternalRetries: 2, ExternalTimeout: "10s"},
+		},
+		{
+			output: Output{CheckExternalLinks: true, ExternalPerHost: 2, ExternalRate: 2000000000, ExternalRetries: 2, ExternalTimeout: "10s"},
+			want:   []string{"external_links_rate"},
+		},
+		{
+			output: Output{
+				CheckExternalLinks: true,
+				ExternalInclude:    []string{"(github"},
+				ExternalPerHost:    0,
+				ExternalRate:       0,
+				ExternalRetries:    -1,
+				ExternalTimeout:    "ten seconds",
+			},
+			want: []string{
+				"external_links_include",
+				"external_links_rate",
+				"external_links_per_host",
+				"external_links_retries",
+				"external_links_timeout",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		var got []string
+
+		err := test.output.Validate()
+		if err != nil {
+			for _, problem := range err.(*ValidationError).Problems {
+				got = append(got, problem.Parameter)
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Validate problems are %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestCollectExternalLinks_SiteBaseURL(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	for path, content := range map[string]string{
+		"/site/hugo.toml": `baseURL = "https://host.example.com/docs/"`,
+		"/site/public/index.html": `<html>
+<a href="https://host.example.com/docs/css/main.css">Styles</a>
+<a href="https://github.com/go-vela/vela-hugo">GitHub</a>
+</html>`,
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	// setup types
+	p := &Plugin{
+		Build:  &Build{},
+		Config: &Config{Directory: "config", SourceDirectory: "/site"},
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	want := []*ExternalLink{
+		{URL: "https://github.com/go-vela/vela-hugo", Sources: []string{"index.html:3"}},
+	}
+
+	// links to the host of the site are not requested as external links
+	got, err := collectExternalLinks("/site/public", p.siteBaseURL(), m, nil, nil)
+	if err != nil {
+		t.Fatalf("collectExternalLinks returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectExternalLinks is %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// setup tests
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "120", want: 2 * time.Minute},
+		{value: "-5", want: 0},
+		{value: "Wed, 01 Jan 2025 00:00:30 GMT", want: 30 * time.Second},
+		{value: "Tue, 31 Dec 2024 23:59:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := retryAfter(test.value, now)
+
+		if got != test.want {
+			t.Errorf("retryAfter for %q is %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestLinkChecker_Workers(t *testing.T) {
+	var active, peak atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		current := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limit := make(chan time.Time)
+	close(limit)
+
+	c := &linkChecker{
+		client:  server.Client(),
+		limit:   limit,
+		workers: 3,
+		perHost: 10,
+		hosts:   make(map[string]chan struct{}),
+	}
+
+	var links []*ExternalLink
+
+	for i := range 20 {
+		links = append(links, &ExternalLink{URL: fmt.Sprintf("%s/%d", server.URL, i)})
+	}
+
+	c.checkAll(t.Context(), links)
+
+	for _, link := range links {
+		if link.Status != http.StatusOK {
+			t.Errorf("status for %s is %d (%s), want 200", link.URL, link.Status, link.Error)
+		}
+	}
+
+	if peak.Load() > 3 {
+		t.Errorf("concurrent requests are %d, want at most 3 workers", peak.Load())
+	}
+}
@@ -0,0 +1,348 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"golang.org/x/net/html"
+)
+
+const (
+	// severity for problems which break the page.
+	_severityError = "error"
+	// severity for problems which degrade the page.
+	_severityWarning = "warning"
+	// threshold which never fails the step.
+	_severityNone = "none"
+	// name of the file the html validation report is written to.
+	_htmlReportFile = "html-validation.json"
+)
+
+// severities contains the rank of each severity, where higher ranks are more severe.
+var severities = map[string]int{
+	_severityNone:    0,
+	_severityWarning: 1,
+	_severityError:   2,
+}
+
+// HTMLIssue represents a problem found when validating a rendered page.
+type HTMLIssue struct {
+	// line of the page the problem is on
+	Line int `json:"line"`
+	// severity of the problem
+	Severity string `json:"severity"`
+	// name of the check which found the problem
+	Rule string `json:"rule"`
+	// description of the problem
+	Message string `json:"message"`
+}
+
+// HTMLReport represents the problems found for a rendered page.
+type HTMLReport struct {
+	// slash separated path relative to the output directory
+	Page string `json:"page"`
+	// problems found on the page
+	Issues []*HTMLIssue `json:"issues"`
+}
+
+// anchor represents an open anchor element while validating a page.
+type anchor struct {
+	// line the anchor starts on
+	line int
+	// url the anchor links to
+	href string
+	// anchor links to another resource
+	link bool
+	// anchor contains text describing the link
+	text bool
+}
+
+// validatePage checks the HTML for duplicate ids, a missing title or lang,
+// images without alt text, empty links and nested anchors.
+func validatePage(r io.Reader) ([]*HTMLIssue, error) {
+	var (
+		issues  []*HTMLIssue
+		anchors []*anchor
+		title   *strings.Builder
+		hasLang bool
+		titled  bool
+		svg     int
+	)
+
+	add := func(line int, severity, rule, format string, args ...any) {
+		issues = append(issues, &HTMLIssue{Line: line, Severity: severity, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	ids := make(map[string]int)
+
+	z := html.NewTokenizer(r)
+	line := 1
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return nil, z.Err()
+			}
+
+			break
+		}
+
+		// capture the line the token starts on before moving past it
+		start := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+
+		token := z.Token()
+
+		switch tt {
+		case html.TextToken:
+			if len(strings.TrimSpace(token.Data)) == 0 {
+				continue
+			}
+
+			if title != nil {
+				title.WriteString(token.Data)
+			}
+
+			for _, a := range anchors {
+				a.text = true
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			attrs := make(map[string]string)
+
+			for _, attr := range token.Attr {
+				attrs[attr.Key] = attr.Val
+			}
+
+			if id, ok := attrs["id"]; ok {
+				if first, ok := ids[id]; ok {
+					add(start, _severityError, "duplicate-id", "id %q is already used on line %d", id, first)
+				} else {
+					ids[id] = start
+				}
+			}
+
+			// accessible names describe the element the same as text
+			if len(strings.TrimSpace(attrs["aria-label"])) > 0 || len(strings.TrimSpace(attrs["title"])) > 0 {
+				for _, a := range anchors {
+					a.text = true
+				}
+			}
+
+			switch token.Data {
+			case "html":
+				hasLang = len(strings.TrimSpace(attrs["lang"])) > 0
+			case "svg":
+				if tt == html.StartTagToken {
+					svg++
+				}
+			case "title":
+				// titles in svg images describe the image instead of the page
+				if svg > 0 {
+					continue
+				}
+
+				titled = true
+
+				if tt == html.StartTagToken {
+					title = new(strings.Builder)
+				}
+			case "img":
+				alt, ok := attrs["alt"]
+				if !ok {
+					add(start, _severityWarning, "missing-alt", "image %s has no alt text", attrs["src"])
+				}
+
+				if len(strings.TrimSpace(alt)) > 0 {
+					for _, a := range anchors {
+						a.text = true
+					}
+				}
+			case "a":
+				if len(anchors) > 0 {
+					add(start, _severityError, "nested-anchor", "anchor is nested in the anchor on line %d", anchors[len(anchors)-1].line)
+				}
+
+				href, link := attrs["href"]
+
+				a := &anchor{
+					line: start,
+					href: href,
+					link: link,
+					text: len(strings.TrimSpace(attrs["aria-label"])) > 0 || len(strings.TrimSpace(attrs["title"])) > 0,
+				}
+
+				// check if the anchor can't contain any text
+				if tt == html.SelfClosingTagToken {
+					if a.link && !a.text {
+						add(start, _severityWarning, "empty-link", "link to %s has no text", a.href)
+					}
+
+					continue
+				}
+
+				anchors = append(anchors, a)
+			}
+		case html.EndTagToken:
+			switch token.Data {
+			case "svg":
+				svg = max(svg-1, 0)
+			case "title":
+				if title != nil && len(strings.TrimSpace(title.String())) == 0 {
+					add(start, _severityError, "missing-title", "title is empty")
+				}
+
+				title = nil
+			case "a":
+				if len(anchors) == 0 {
+					continue
+				}
+
+				a := anchors[len(anchors)-1]
+				anchors = anchors[:len(anchors)-1]
+
+				if a.link && !a.text {
+					add(a.line, _severityWarning, "empty-link", "link to %s has no text", a.href)
+				}
+			}
+		}
+	}
+
+	if !titled {
+		add(1, _severityError, "missing-title", "page has no title")
+	}
+
+	if !hasLang {
+		add(1, _severityWarning, "missing-lang", "html element has no lang attribute")
+	}
+
+	// order the problems found at the end of the page by line
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+
+	return issues, nil
+}
+
+// validateHTML checks every rendered page in the manifest and
+// returns the reports for the pages with problems.
+func validateHTML(output string, m *Manifest) ([]*HTMLReport, error) {
+	var reports []*HTMLReport
+
+	for _, file := range m.Files {
+		if !isPage(file.Path) {
+			continue
+		}
+
+		f, err := appFS.Open(filepath.Join(output, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return nil, err
+		}
+
+		issues, err := validatePage(f)
+
+		f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to validate page %s: %w", file.Path, err)
+		}
+
+		if len(issues) > 0 {
+			reports = append(reports, &HTMLReport{Page: file.Path, Issues: issues})
+		}
+	}
+
+	return reports, nil
+}
+
+// writeHTMLReports outputs the problems for every page to the provided path.
+func writeHTMLReports(path string, reports []*HTMLReport) error {
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("writing html validation report to %s", path)
+
+	return afero.WriteFile(appFS, path, append(data, '\n'), 0644)
+}
+
+// printHTMLReports prints the problems for every page as a table.
+func printHTMLReports(reports []*HTMLReport) {
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "  PAGE\tLINE\tSEVERITY\tRULE\tPROBLEM")
+
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\n", report.Page, issue.Line, issue.Severity, issue.Rule, issue.Message)
+		}
+	}
+
+	w.Flush()
+}
+
+// validateHTML verifies the rendered pages have no problems at or above the threshold.
+func (o *Output) validateHTML(output, dir string, m *Manifest) error {
+	logrus.Info("validating rendered pages")
+
+	reports, err := validateHTML(output, m)
+	if err != nil {
+		return err
+	}
+
+	err = writeHTMLReports(filepath.Join(dir, _htmlReportFile), reports)
+	if err != nil {
+		return err
+	}
+
+	if len(reports) == 0 {
+		logrus.Info("no problems found on the rendered pages")
+
+		return nil
+	}
+
+	printHTMLReports(reports)
+
+	var total, failing int
+
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			total++
+
+			if o.HTMLThreshold != _severityNone && severities[issue.Severity] >= severities[o.HTMLThreshold] {
+				failing++
+			}
+		}
+	}
+
+	if failing > 0 {
+		return fmt.Errorf("rendered pages have problems: %d problem(s) at or above %s severity found", failing, o.HTMLThreshold)
+	}
+
+	logrus.Warnf("%d problem(s) below %s severity found on the rendered pages", total, o.HTMLThreshold)
+
+	return nil
+}
+
+// validateThreshold captures the problems with the html validation configuration.
+func (o *Output) validateThreshold(v *ValidationError) {
+	// check if the rendered pages should be validated
+	if !o.ValidateHTML {
+		return
+	}
+
+	if _, ok := severities[o.HTMLThreshold]; !ok {
+		v.add("validate_html_threshold", fmt.Sprintf("use %s, %s or %s", _severityError, _severityWarning, _severityNone),
+			"invalid threshold provided: %s", o.HTMLThreshold)
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidatePage(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			name: "valid page",
+			page: `<!DOCTYPE html>
+<html lang="en">
+<head><title>Docs</title></head>
+<body>
+  <h1 id="intro">Intro</h1>
+  <img src="/logo.png" alt="">
+  <a href="/"><img src="/home.png" alt="Home"></a>
+  <a href="/search/" aria-label="Search"><svg><title>Search</title></svg></a>
+  <a id="anchor"></a>
+  <a href="/docs/">Docs</a>
+</body>
+</html>`,
+		},
+		{
+			name: "invalid page",
+			page: `<!DOCTYPE html>
+<html>
+<head></head>
+<body>
+  <h1 id="intro">Intro</h1>
+  <h2 id="intro">Intro</h2>
+  <img src="/logo.png">
+  <a href="/docs/">
+    <a href="/guide/">Guide</a>
+  </a>
+  <a href="/empty/"> </a>
+</body>
+</html>`,
+			want: []string{
+				"1 error missing-title page has no title",
+				"1 warning missing-lang html element has no lang attribute",
+				`6 error duplicate-id id "intro" is already used on line 5`,
+				"7 warning missing-alt image /logo.png has no alt text",
+				"9 error nested-anchor anchor is nested in the anchor on line 8",
+				"11 warning empty-link link to /empty/ has no text",
+			},
+		},
+		{
+			name: "empty title",
+			page: `<html lang="en"><head><title> </title></head></html>`,
+			want: []string{"1 error missing-title title is empty"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		issues, err := validatePage(strings.NewReader(test.page))
+		if err != nil {
+			t.Errorf("%s validatePage returned err: %v", test.name, err)
+		}
+
+		var got []string
+
+		for _, issue := range issues {
+			got = append(got, fmt.Sprintf("%d %s %s %s", issue.Line, issue.Severity, issue.Rule, issue.Message))
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s validatePage is %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOutput_Exec_ValidateHTML(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	for path, content := range map[string]string{
+		"/site/public/index.html":      `<html lang="en"><title>Home</title><img src="/logo.png"></html>`,
+		"/site/public/docs/index.html": `<html lang="en"><title>Docs</title></html>`,
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	// setup tests
+	tests := []struct {
+		failure   bool
+		threshold string
+	}{
+		{failure: false, threshold: _severityError},
+		{failure: true, threshold: _severityWarning},
+		{failure: false, threshold: _severityNone},
+	}
+
+	// run tests
+	for _, test := range tests {
+		o := &Output{ValidateHTML: true, HTMLThreshold: test.threshold}
+
+		err := o.Exec(t.Context(), "/site/public", "/site", "")
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("Exec with %s threshold should have returned err", test.threshold)
+			}
+		} else if err != nil {
+			t.Errorf("Exec with %s threshold returned err: %v", test.threshold, err)
+		}
+	}
+
+	data, err := a.ReadFile("/site/html-validation.json")
+	if err != nil {
+		t.Fatalf("unable to read html validation report: %v", err)
+	}
+
+	var got []*HTMLReport
+
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("unable to parse html validation report: %v", err)
+	}
+
+	want := []*HTMLReport{
+		{
+			Page:   "index.html",
+			Issues: []*HTMLIssue{{Line: 1, Severity: _severityWarning, Rule: "missing-alt", Message: "image /logo.png has no alt text"}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("html validation report is %s", data)
+	}
+}
+
+func TestOutput_Validate_HTMLThreshold(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		output  Output
+		failure bool
+	}{
+		{output: Output{HTMLThreshold: "fatal"}, failure: false},
+		{output: Output{ValidateHTML: true, HTMLThreshold: _severityWarning}, failure: false},
+		{output: Output{ValidateHTML: true, HTMLThreshold: "fatal"}, failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.output.Validate()
+
+		if test.failure != (err != nil) {
+			t.Errorf("Validate for %s threshold returned %v", test.output.HTMLThreshold, err)
+		}
+	}
+}
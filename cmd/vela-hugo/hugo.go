@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 
@@ -21,12 +22,99 @@ const (
 	_checksum = "https://github.com/gohugoio/hugo/releases/download/v%s/%s_%s_checksums.txt"
 )
 
+// hugoVersion matches the output from the `hugo version` command, e.g.
+//
+//	hugo v0.148.2-40c3d8233d4b123eff74725e5766fc6272f0a84d+extended linux/amd64 BuildDate=2025-07-27T12:43:24Z
+var hugoVersion = regexp.MustCompile(`v(\d+\.\d+\.\d+)(?:-([0-9A-Fa-f]{7,40}))?((?:[+/][A-Za-z]+)*)\s+(\w+)/(\w+)(?:\s+BuildDate[=:]\s?(\S+))?`)
+
+// HugoInfo represents the information for the hugo binary.
+type HugoInfo struct {
+	// semantic version of the binary
+	Version string
+	// git commit the binary was built from
+	Commit string
+	// whether the binary is the extended edition
+	Extended bool
+	// operating system the binary was built for
+	OS string
+	// architecture the binary was built for
+	Arch string
+	// date the binary was built
+	BuildDate string
+}
+
+// parseHugoInfo parses the output from the
+// `hugo version` command into a HugoInfo.
+func parseHugoInfo(out []byte) (*HugoInfo, error) {
+	match := hugoVersion.FindStringSubmatch(string(out))
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse hugo version: %s", strings.TrimSpace(string(out)))
+	}
+
+	return &HugoInfo{
+		Version:   match[1],
+		Commit:    match[2],
+		Extended:  strings.Contains(match[3], "extended"),
+		OS:        match[4],
+		Arch:      match[5],
+		BuildDate: match[6],
+	}, nil
+}
+
 func install(ctx context.Context, extendedBinary bool, customVer, defaultVer string) error {
 	// use custom filesystem which enables us to test
 	a := &afero.Afero{
 		Fs: appFS,
 	}
 
+	// resolve the url to download the requested binary from
+	fullURL, err := downloadURL(extendedBinary, customVer, defaultVer)
+	if err != nil {
+		return err
+	}
+
+	// are we using the included default
+	// (non-extended) version?
+	// if so, no need to download anything
+	if len(fullURL) == 0 {
+		return nil
+	}
+
+	// rename the old hugo binary since we can't overwrite it for now
+	//
+	// https://github.com/hashicorp/go-getter/issues/219
+	err = a.Rename(_hugo, fmt.Sprintf("%s.default", _hugo))
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("downloading hugo version from: %s", fullURL)
+
+	// send the HTTP request to install hugo
+	_, err = getter.Get(ctx, _hugoTmp, fullURL)
+	if err != nil {
+		return err
+	}
+
+	// getter installed a directory of files, move the binary from that to the _hugo location
+	err = a.Rename(_hugoTmp+"/hugo", _hugo)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("changing ownership of file: %s", _hugo)
+	// ensure the hugo binary is executable
+	err = a.Chmod(_hugo, 0700)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// downloadURL resolves the url to download the requested hugo binary
+// from, which is empty when the default binary in the image is used.
+func downloadURL(extendedBinary bool, customVer, defaultVer string) (string, error) {
 	// setup vars for building the _download url
 	//   based off of https://github.com/gohugoio/hugo/releases for the naming convention
 	binary := "hugo"
@@ -53,7 +141,7 @@ func install(ctx context.Context, extendedBinary bool, customVer, defaultVer str
 	// into semantic version struct
 	ver, err := semver.NewVersion(customVer)
 	if err != nil {
-		return fmt.Errorf("not a valid version: %s", customVer)
+		return "", fmt.Errorf("not a valid version: %s", customVer)
 	}
 
 	// get the version without leading "v",
@@ -68,7 +156,7 @@ func install(ctx context.Context, extendedBinary bool, customVer, defaultVer str
 	// (non-extended) version?
 	// if so, no need to download anything
 	if isDefaultVersion && !extendedBinary {
-		return nil
+		return "", nil
 	}
 
 	// let user know that a custom version
@@ -86,39 +174,9 @@ func install(ctx context.Context, extendedBinary bool, customVer, defaultVer str
 		archType = "universal"
 	}
 
-	// rename the old hugo binary since we can't overwrite it for now
-	//
-	// https://github.com/hashicorp/go-getter/issues/219
-	err = a.Rename(_hugo, fmt.Sprintf("%s.default", _hugo))
-	if err != nil {
-		return err
-	}
-
 	// create the download URL to install hugo - https://github.com/gohugoio/hugo/releases
 	url := fmt.Sprintf(_download, verWithoutV, binary, verWithoutV, osName, archType)
 	checksumURL := fmt.Sprintf(_checksum, verWithoutV, checksum, verWithoutV)
-	fullURL := fmt.Sprintf("%s?checksum=file:%s", url, checksumURL)
 
-	logrus.Infof("downloading hugo version from: %s", fullURL)
-
-	// send the HTTP request to install hugo
-	_, err = getter.Get(ctx, _hugoTmp, fullURL)
-	if err != nil {
-		return err
-	}
-
-	// getter installed a directory of files, move the binary from that to the _hugo location
-	err = a.Rename(_hugoTmp+"/hugo", _hugo)
-	if err != nil {
-		return err
-	}
-
-	logrus.Debugf("changing ownership of file: %s", _hugo)
-	// ensure the hugo binary is executable
-	err = a.Chmod(_hugo, 0700)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return fmt.Sprintf("%s?checksum=file:%s", url, checksumURL), nil
 }
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseHugoInfo(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		output  string
+		want    *HugoInfo
+	}{
+		{
+			failure: false,
+			name:    "extended binary",
+			output:  "hugo v0.148.2-40c3d8233d4b123eff74725e5766fc6272f0a84d+extended linux/amd64 BuildDate=2025-07-27T12:43:24Z VendorInfo=gohugoio\n",
+			want: &HugoInfo{
+				Version:   "0.148.2",
+				Commit:    "40c3d8233d4b123eff74725e5766fc6272f0a84d",
+				Extended:  true,
+				OS:        "linux",
+				Arch:      "amd64",
+				BuildDate: "2025-07-27T12:43:24Z",
+			},
+		},
+		{
+			failure: false,
+			name:    "legacy binary",
+			output:  "Hugo Static Site Generator v0.80.0-792EF0F4 linux/amd64 BuildDate: 2020-12-31T13:37:57Z\n",
+			want: &HugoInfo{
+				Version:   "0.80.0",
+				Commit:    "792EF0F4",
+				Extended:  false,
+				OS:        "linux",
+				Arch:      "amd64",
+				BuildDate: "2020-12-31T13:37:57Z",
+			},
+		},
+		{
+			failure: true,
+			name:    "invalid output",
+			output:  "command not found",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseHugoInfo([]byte(test.output))
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s parseHugoInfo should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s parseHugoInfo returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s parseHugoInfo is %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
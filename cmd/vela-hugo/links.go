@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+)
+
+// linkAttributes contains the attributes which link to another resource for each element.
+var linkAttributes = map[string][]string{
+	"a":      {"href"},
+	"area":   {"href"},
+	"audio":  {"src"},
+	"embed":  {"src"},
+	"iframe": {"src"},
+	"img":    {"src", "srcset"},
+	"link":   {"href"},
+	"script": {"src"},
+	"source": {"src", "srcset"},
+	"track":  {"src"},
+	"video":  {"src", "poster"},
+}
+
+// Link represents a link to another resource from a rendered page.
+type Link struct {
+	// line of the page the link is on
+	Line int
+	// url the link refers to
+	URL string
+}
+
+// Page represents the links and anchors of a rendered page.
+type Page struct {
+	// slash separated path relative to the output directory
+	Path string
+	// anchors which can be linked to on the page
+	Anchors map[string]bool
+	// links to other resources from the page
+	Links []*Link
+}
+
+// BrokenLink represents a link which doesn't resolve to the output directory.
+type BrokenLink struct {
+	// page the link is on
+	Page string
+	// line of the page the link is on
+	Line int
+	// url the link refers to
+	URL string
+	// reason the link is broken
+	Reason string
+}
+
+// parsePage captures the links and anchors from the HTML, tracking the line
+// each link is on, which uses a tokenizer to tolerate invalid markup.
+func parsePage(r io.Reader) (*Page, error) {
+	p := &Page{Anchors: make(map[string]bool)}
+
+	z := html.NewTokenizer(r)
+	line := 1
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() == io.EOF {
+				return p, nil
+			}
+
+			return nil, z.Err()
+		}
+
+		// capture the line the token starts on before moving past it
+		start := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := z.Token()
+
+		for _, attr := range token.Attr {
+			switch {
+			case attr.Key == "id", token.Data == "a" && attr.Key == "name":
+				p.Anchors[attr.Val] = true
+			case isLinkAttribute(token.Data, attr.Key):
+				for _, value := range linkValues(attr.Key, attr.Val) {
+					p.Links = append(p.Links, &Link{Line: start, URL: value})
+				}
+			}
+		}
+	}
+}
+
+// isLinkAttribute returns whether the attribute of the element links to another resource.
+func isLinkAttribute(element, attribute string) bool {
+	for _, key := range linkAttributes[element] {
+		if key == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// linkValues returns the urls from the attribute, which
+// contains multiple candidates for a srcset attribute.
+func linkValues(attribute, value string) []string {
+	if attribute != "srcset" {
+		return []string{strings.TrimSpace(value)}
+	}
+
+	var values []string
+
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 {
+			values = append(values, fields[0])
+		}
+	}
+
+	return values
+}
+
+// readPages parses the rendered pages from the manifest by their path.
+func readPages(output string, m *Manifest) (map[string]*Page, error) {
+	pages := make(map[string]*Page)
+
+	for _, file := range m.Files {
+		if !isPage(file.Path) {
+			continue
+		}
+
+		f, err := appFS.Open(filepath.Join(output, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := parsePage(f)
+
+		f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse page %s: %w", file.Path, err)
+		}
+
+		page.Path = file.Path
+		pages[file.Path] = page
+	}
+
+	return pages, nil
+}
+
+// checkLinks resolves every link on the rendered pages relative to the
+// base url and returns the links to files or anchors which don't exist.
+func checkLinks(output, baseURL string, m *Manifest) ([]*BrokenLink, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse base url %s: %w", baseURL, err)
+	}
+
+	// the site is served from the root when no path is provided
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	files := make(map[string]bool)
+
+	for _, file := range m.Files {
+		files[file.Path] = true
+	}
+
+	pages, err := readPages(output, m)
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []*BrokenLink
+
+	for _, file := range m.Files {
+		page, ok := pages[file.Path]
+		if !ok {
+			continue
+		}
+
+		// resolve the url the page is served from
+		source := base.ResolveReference(&url.URL{Path: strings.TrimPrefix(pageURL(page.Path), "/")})
+
+		for _, link := range page.Links {
+			reason := resolveLink(base, source, link.URL, files, pages)
+			if len(reason) == 0 {
+				continue
+			}
+
+			broken = append(broken, &BrokenLink{Page: page.Path, Line: link.Line, URL: link.URL, Reason: reason})
+		}
+	}
+
+	return broken, nil
+}
+
+// resolveLink returns the reason the link from the page is broken,
+// which is empty for valid links and links to other sites.
+func resolveLink(base, source *url.URL, link string, files map[string]bool, pages map[string]*Page) string {
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "invalid url"
+	}
+
+	// check if the link is for another scheme, e.g. mailto: or data:
+	if len(ref.Scheme) > 0 && ref.Scheme != "http" && ref.Scheme != "https" {
+		return ""
+	}
+
+	target := source.ResolveReference(ref)
+
+	// check if the link is for another site
+	if len(ref.Host) > 0 && !strings.EqualFold(target.Host, base.Host) {
+		return ""
+	}
+
+	// check if the link is outside of the site
+	if !strings.HasPrefix(target.Path, base.Path) {
+		return fmt.Sprintf("outside of the base url %s", base.String())
+	}
+
+	file := strings.TrimPrefix(target.Path, base.Path)
+
+	// resolve the file served for the path
+	switch {
+	case len(file) == 0 || strings.HasSuffix(file, "/"):
+		file += "index.html"
+	case !files[file] && files[file+"/index.html"]:
+		file += "/index.html"
+	}
+
+	if !files[file] {
+		return fmt.Sprintf("no file found @ %s", file)
+	}
+
+	// check if the link is for an anchor on the page
+	if len(target.Fragment) == 0 || target.Fragment == "top" {
+		return ""
+	}
+
+	page, ok := pages[file]
+	if ok && !page.Anchors[target.Fragment] {
+		return fmt.Sprintf("no anchor #%s found on %s", target.Fragment, file)
+	}
+
+	return ""
+}
+
+// linkErr prints the broken links as a table, since the log
+// output escapes newlines, and returns a summary error.
+func linkErr(broken []*BrokenLink) error {
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "  PAGE\tLINE\tLINK\tPROBLEM")
+
+	for _, link := range broken {
+		fmt.Fprintf(w, "  %s\t%d\t%s\t%s\n", link.Page, link.Line, link.URL, link.Reason)
+	}
+
+	w.Flush()
+
+	return fmt.Errorf("output contains broken links: %d broken link(s) found", len(broken))
+}
+
+// checkLinks verifies the links on the rendered pages resolve to the output directory.
+func (o *Output) checkLinks(output, baseURL string, m *Manifest) error {
+	logrus.Info("checking internal links")
+
+	broken, err := checkLinks(output, baseURL, m)
+	if err != nil {
+		return err
+	}
+
+	if len(broken) > 0 {
+		return linkErr(broken)
+	}
+
+	logrus.Infof("no broken links found")
+
+	return nil
+}
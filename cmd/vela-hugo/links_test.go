@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePage(t *testing.T) {
+	// setup types
+	page := `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <link rel="stylesheet" href="/css/main.css">
+  <script src="/js/app.js"></script>
+</head>
+<body>
+  <h1 id="intro">Intro</h1>
+  <a name="legacy"></a>
+  <a
+    href="/docs/#setup">Setup</a>
+  <img src="/logo.png" srcset="/logo-1x.png 1x, /logo-2x.png 2x">
+  <p>No links here</p>
+</body>
+</html>`
+
+	got, err := parsePage(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("parsePage returned err: %v", err)
+	}
+
+	wantLinks := []*Link{
+		{Line: 4, URL: "/css/main.css"},
+		{Line: 5, URL: "/js/app.js"},
+		{Line: 10, URL: "/docs/#setup"},
+		{Line: 12, URL: "/logo.png"},
+		{Line: 12, URL: "/logo-1x.png"},
+		{Line: 12, URL: "/logo-2x.png"},
+	}
+
+	if !reflect.DeepEqual(got.Links, wantLinks) {
+		for _, link := range got.Links {
+			t.Logf("link %+v", link)
+		}
+
+		t.Errorf("parsePage links are %v, want %v", got.Links, wantLinks)
+	}
+
+	wantAnchors := map[string]bool{"intro": true, "legacy": true}
+
+	if !reflect.DeepEqual(got.Anchors, wantAnchors) {
+		t.Errorf("parsePage anchors are %v, want %v", got.Anchors, wantAnchors)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	for path, content := range map[string]string{
+		"/site/public/index.html": `<html>
+<a href="docs/">Docs</a>
+<a href="/docs/#install">Install</a>
+<a href="/docs/#missing">Missing</a>
+<a href="/blog/">Blog</a>
+<a href="https://docs.example.com/css/main.css">Styles</a>
+<a href="https://github.com/go-vela/vela-hugo">GitHub</a>
+<a href="mailto:docs@example.com">Email</a>
+<a href="#top">Top</a>
+</html>`,
+		"/site/public/docs/index.html": `<html>
+<h2 id="install">Install</h2>
+<a href="../">Home</a>
+<a href="guide">Guide</a>
+<img src="../images/missing.png">
+<a href="#install">Install</a>
+</html>`,
+		"/site/public/docs/guide/index.html": `<html><a href="../../index.html">Home</a></html>`,
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	want := []*BrokenLink{
+		{Page: "docs/index.html", Line: 5, URL: "../images/missing.png", Reason: "no file found @ images/missing.png"},
+		{Page: "index.html", Line: 4, URL: "/docs/#missing", Reason: "no anchor #missing found on docs/index.html"},
+		{Page: "index.html", Line: 5, URL: "/blog/", Reason: "no file found @ blog/index.html"},
+	}
+
+	got, err := checkLinks("/site/public", "https://docs.example.com/", m)
+	if err != nil {
+		t.Fatalf("checkLinks returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		for _, link := range got {
+			t.Logf("broken link %+v", link)
+		}
+
+		t.Errorf("checkLinks is %v, want %v", got, want)
+	}
+}
+
+func TestCheckLinks_BasePath(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	err := a.WriteFile("/site/public/index.html", []byte(`<html>
+<a href="/docs/docs/">Docs</a>
+<a href="/other/">Other</a>
+<a href="/docs/css/main.css">Styles</a>
+</html>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	want := []*BrokenLink{
+		{Page: "index.html", Line: 3, URL: "/other/", Reason: "outside of the base url /docs/"},
+	}
+
+	got, err := checkLinks("/site/public", "/docs/", m)
+	if err != nil {
+		t.Fatalf("checkLinks returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkLinks is %+v, want %+v", got[0], want[0])
+	}
+}
+
+func TestOutput_Exec_CheckLinks(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	// setup types
+	o := &Output{CheckLinks: true}
+
+	err := o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	err = a.WriteFile("/site/public/index.html", []byte(`<a href="/missing/">Missing</a>`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = o.Exec(t.Context(), "/site/public", "/site", "https://docs.example.com/")
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestCheckLinks_SiteBaseURL(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	for path, content := range map[string]string{
+		"/site/hugo.toml": `baseURL = "https://host.example.com/docs/"`,
+		"/site/public/index.html": `<html>
+<a href="/docs/docs/">Docs</a>
+<a href="https://host.example.com/docs/css/main.css">Styles</a>
+<a href="https://host.example.com/docs/missing/">Missing</a>
+</html>`,
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	// setup types
+	p := &Plugin{
+		Build:  &Build{},
+		Config: &Config{Directory: "config", SourceDirectory: "/site"},
+	}
+
+	baseURL := p.siteBaseURL()
+	if baseURL != "https://host.example.com/docs/" {
+		t.Fatalf("siteBaseURL is %s, want the site configuration base url", baseURL)
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	want := []*BrokenLink{
+		{Page: "index.html", Line: 4, URL: "https://host.example.com/docs/missing/", Reason: "no file found @ missing/index.html"},
+	}
+
+	got, err := checkLinks("/site/public", baseURL, m)
+	if err != nil {
+		t.Fatalf("checkLinks returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		for _, link := range got {
+			t.Logf("broken link %+v", link)
+		}
+
+		t.Errorf("checkLinks is %v, want %v", got, want)
+	}
+
+	// the base url parameter takes precedence over the site configuration
+	p.Build.BaseURL = "https://preview.example.com/"
+
+	if got := p.siteBaseURL(); got != p.Build.BaseURL {
+		t.Errorf("siteBaseURL is %s, want %s", got, p.Build.BaseURL)
+	}
+}
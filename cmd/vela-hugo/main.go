@@ -22,15 +22,6 @@ func main() {
 	// capture application version information
 	v := version.New()
 
-	// serialize the version information as pretty JSON
-	bytes, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	// output the version information to stdout
-	fmt.Fprintf(os.Stdout, "%s\n", string(bytes))
-
 	// create new CLI application
 	app := &cli.Command{
 		Name:      "vela-hugo",
@@ -45,6 +36,20 @@ func main() {
 		Version: v.Semantic(),
 		Action:  run,
 
+		// Plugin Commands
+		Commands: []*cli.Command{
+			{
+				Name:   "doctor",
+				Usage:  "inspect the workspace and recommend plugin parameters without building the site",
+				Action: doctor,
+			},
+			{
+				Name:   "schema",
+				Usage:  "output the JSON Schema for the plugin parameters",
+				Action: schema,
+			},
+		},
+
 		// Plugin Flags
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -68,6 +73,26 @@ func main() {
 					cli.File("/vela/secrets/hugo/extended"),
 				),
 			},
+			&cli.BoolFlag{
+				Name:  "hugo.dry_run",
+				Usage: "validate the configuration and print the commands to run without building the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_DRY_RUN"),
+					cli.EnvVar("HUGO_DRY_RUN"),
+					cli.File("/vela/parameters/hugo/dry_run"),
+					cli.File("/vela/secrets/hugo/dry_run"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "hugo.strict_parameters",
+				Usage: "fail instead of warn when an unknown parameter is provided",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_STRICT_PARAMETERS"),
+					cli.EnvVar("HUGO_STRICT_PARAMETERS"),
+					cli.File("/vela/parameters/hugo/strict_parameters"),
+					cli.File("/vela/secrets/hugo/strict_parameters"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "hugo.version",
 				Usage: "set hugo version for plugin",
@@ -79,6 +104,39 @@ func main() {
 				),
 			},
 
+			// Artifact Flags
+			&cli.BoolFlag{
+				Name:  "artifact.enabled",
+				Usage: "package the output directory into an artifact after building the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ARTIFACT"),
+					cli.EnvVar("HUGO_ARTIFACT"),
+					cli.File("/vela/parameters/hugo/artifact"),
+					cli.File("/vela/secrets/hugo/artifact"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "artifact.format",
+				Usage: "format of the artifact - options: (tar.gz|zip)",
+				Value: "tar.gz",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ARTIFACT_FORMAT"),
+					cli.EnvVar("HUGO_ARTIFACT_FORMAT"),
+					cli.File("/vela/parameters/hugo/artifact_format"),
+					cli.File("/vela/secrets/hugo/artifact_format"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "artifact.path",
+				Usage: "filesystem path to write the artifact to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ARTIFACT_PATH"),
+					cli.EnvVar("HUGO_ARTIFACT_PATH"),
+					cli.File("/vela/parameters/hugo/artifact_path"),
+					cli.File("/vela/secrets/hugo/artifact_path"),
+				),
+			},
+
 			// Build Flags
 			&cli.StringFlag{
 				Name:  "build.base_url",
@@ -90,6 +148,16 @@ func main() {
 					cli.File("/vela/secrets/hugo/base_url"),
 				),
 			},
+			&cli.BoolFlag{
+				Name:  "build.allow_relative_base_url",
+				Usage: "allow a relative or protocol-relative base url, e.g. /docs/ or //example.com/",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ALLOW_RELATIVE_BASE_URL"),
+					cli.EnvVar("HUGO_ALLOW_RELATIVE_BASE_URL"),
+					cli.File("/vela/parameters/hugo/allow_relative_base_url"),
+					cli.File("/vela/secrets/hugo/allow_relative_base_url"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "build.draft",
 				Usage: "include content marked as draft",
@@ -120,6 +188,16 @@ func main() {
 					cli.File("/vela/secrets/hugo/future"),
 				),
 			},
+			&cli.BoolFlag{
+				Name:  "build.reproducible",
+				Usage: "verify the site builds reproducibly by building it twice and comparing the output",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REPRODUCIBLE"),
+					cli.EnvVar("HUGO_REPRODUCIBLE"),
+					cli.File("/vela/parameters/hugo/reproducible"),
+					cli.File("/vela/secrets/hugo/reproducible"),
+				),
+			},
 
 			// Config Flags
 			&cli.StringFlag{
@@ -165,7 +243,7 @@ func main() {
 			},
 			&cli.StringFlag{
 				Name:  "config.file",
-				Usage: "name of config file in config directory",
+				Usage: "config file(s) relative to the source directory",
 				Sources: cli.NewValueSourceChain(
 					cli.EnvVar("PARAMETER_CONFIG_FILE"),
 					cli.EnvVar("HUGO_CONFIG_FILE"),
@@ -193,6 +271,26 @@ func main() {
 					cli.File("/vela/secrets/hugo/output_directory"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "config.overrides",
+				Usage: "site configuration to override with an additional config file",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_CONFIG_OVERRIDES"),
+					cli.EnvVar("HUGO_CONFIG_OVERRIDES"),
+					cli.File("/vela/parameters/hugo/config_overrides"),
+					cli.File("/vela/secrets/hugo/config_overrides"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "config.params",
+				Usage: "site params to override through the environment",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PARAMS"),
+					cli.EnvVar("HUGO_PARAMS"),
+					cli.File("/vela/parameters/hugo/params"),
+					cli.File("/vela/secrets/hugo/params"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "config.source_directory",
 				Usage: "filesystem path to read files from",
@@ -203,6 +301,342 @@ func main() {
 					cli.File("/vela/secrets/hugo/source_directory"),
 				),
 			},
+			&cli.BoolFlag{
+				Name:  "config.validate_site",
+				Usage: "read and validate the site configuration before building",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_VALIDATE_SITE"),
+					cli.EnvVar("HUGO_VALIDATE_SITE"),
+					cli.File("/vela/parameters/hugo/validate_site"),
+					cli.File("/vela/secrets/hugo/validate_site"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "config.print",
+				Usage: "print the effective configuration hugo builds the site with",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PRINT_CONFIG"),
+					cli.EnvVar("HUGO_PRINT_CONFIG"),
+					cli.File("/vela/parameters/hugo/print_config"),
+					cli.File("/vela/secrets/hugo/print_config"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "config.print_file",
+				Usage: "filesystem path to write the effective configuration to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PRINT_CONFIG_FILE"),
+					cli.EnvVar("HUGO_PRINT_CONFIG_FILE"),
+					cli.File("/vela/parameters/hugo/print_config_file"),
+					cli.File("/vela/secrets/hugo/print_config_file"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "config.baseline",
+				Usage: "filesystem path to the effective configuration to compare against",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_CONFIG_BASELINE"),
+					cli.EnvVar("HUGO_CONFIG_BASELINE"),
+					cli.File("/vela/parameters/hugo/config_baseline"),
+					cli.File("/vela/secrets/hugo/config_baseline"),
+				),
+			},
+
+			// Module Flags
+			&cli.BoolFlag{
+				Name:  "module.report",
+				Usage: "write a report of the hugo modules used by the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MODULE_REPORT"),
+					cli.EnvVar("HUGO_MODULE_REPORT"),
+					cli.File("/vela/parameters/hugo/module_report"),
+					cli.File("/vela/secrets/hugo/module_report"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "module.allowed_licenses",
+				Usage: "licenses allowed for the hugo modules used by the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MODULE_ALLOWED_LICENSES"),
+					cli.EnvVar("HUGO_MODULE_ALLOWED_LICENSES"),
+					cli.File("/vela/parameters/hugo/module_allowed_licenses"),
+					cli.File("/vela/secrets/hugo/module_allowed_licenses"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "module.disallowed_licenses",
+				Usage: "licenses disallowed for the hugo modules used by the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MODULE_DISALLOWED_LICENSES"),
+					cli.EnvVar("HUGO_MODULE_DISALLOWED_LICENSES"),
+					cli.File("/vela/parameters/hugo/module_disallowed_licenses"),
+					cli.File("/vela/secrets/hugo/module_disallowed_licenses"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "module.require_pinned",
+				Usage: "require the hugo modules used by the site to be pinned to a released version",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MODULE_REQUIRE_PINNED"),
+					cli.EnvVar("HUGO_MODULE_REQUIRE_PINNED"),
+					cli.File("/vela/parameters/hugo/module_require_pinned"),
+					cli.File("/vela/secrets/hugo/module_require_pinned"),
+				),
+			},
+
+			// NPM Flags
+			&cli.BoolFlag{
+				Name:  "npm.install",
+				Usage: "install the npm packages for the site before building it",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_NPM_INSTALL"),
+					cli.EnvVar("HUGO_NPM_INSTALL"),
+					cli.File("/vela/parameters/hugo/npm_install"),
+					cli.File("/vela/secrets/hugo/npm_install"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "npm.command",
+				Usage: "command used to install the npm packages for the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_NPM_COMMAND"),
+					cli.EnvVar("HUGO_NPM_COMMAND"),
+					cli.File("/vela/parameters/hugo/npm_command"),
+					cli.File("/vela/secrets/hugo/npm_command"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "npm.cache_directory",
+				Usage: "filesystem path to npm cache directory",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_NPM_CACHE_DIRECTORY"),
+					cli.EnvVar("HUGO_NPM_CACHE_DIRECTORY"),
+					cli.File("/vela/parameters/hugo/npm_cache_directory"),
+					cli.File("/vela/secrets/hugo/npm_cache_directory"),
+				),
+			},
+
+			// Output Flags
+			&cli.StringFlag{
+				Name:  "output.baseline",
+				Usage: "filesystem path or go-getter url for the manifest of a previous build to diff the output against",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_BASELINE_MANIFEST"),
+					cli.EnvVar("HUGO_BASELINE_MANIFEST"),
+					cli.File("/vela/parameters/hugo/baseline_manifest"),
+					cli.File("/vela/secrets/hugo/baseline_manifest"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.budget_file_size",
+				Usage: "maximum size of any single file in the output directory, e.g. 1MB",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_BUDGET_FILE_SIZE"),
+					cli.EnvVar("HUGO_BUDGET_FILE_SIZE"),
+					cli.File("/vela/parameters/hugo/budget_file_size"),
+					cli.File("/vela/secrets/hugo/budget_file_size"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.budget_total_size",
+				Usage: "maximum total size of the output directory, e.g. 50MB",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_BUDGET_TOTAL_SIZE"),
+					cli.EnvVar("HUGO_BUDGET_TOTAL_SIZE"),
+					cli.File("/vela/parameters/hugo/budget_total_size"),
+					cli.File("/vela/secrets/hugo/budget_total_size"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "output.budgets",
+				Usage: "size limits for each file matching a glob pattern, e.g. **/*.js < 200KB",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_BUDGETS"),
+					cli.EnvVar("HUGO_BUDGETS"),
+					cli.File("/vela/parameters/hugo/budgets"),
+					cli.File("/vela/secrets/hugo/budgets"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "output.check_external_links",
+				Usage: "verify the links to other sites from the rendered pages",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_CHECK_EXTERNAL_LINKS"),
+					cli.EnvVar("HUGO_CHECK_EXTERNAL_LINKS"),
+					cli.File("/vela/parameters/hugo/check_external_links"),
+					cli.File("/vela/secrets/hugo/check_external_links"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "output.check_links",
+				Usage: "verify the links on the rendered pages resolve to files and anchors in the output directory",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_CHECK_LINKS"),
+					cli.EnvVar("HUGO_CHECK_LINKS"),
+					cli.File("/vela/parameters/hugo/check_links"),
+					cli.File("/vela/secrets/hugo/check_links"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "output.external_ignore",
+				Usage: "regular expressions for the external links to skip",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_IGNORE"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_IGNORE"),
+					cli.File("/vela/parameters/hugo/external_links_ignore"),
+					cli.File("/vela/secrets/hugo/external_links_ignore"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "output.external_include",
+				Usage: "regular expressions for the external links to check, which checks every link when empty",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_INCLUDE"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_INCLUDE"),
+					cli.File("/vela/parameters/hugo/external_links_include"),
+					cli.File("/vela/secrets/hugo/external_links_include"),
+				),
+			},
+			&cli.IntFlag{
+				Name:  "output.external_per_host",
+				Usage: "maximum concurrent requests to each host when checking external links",
+				Value: 2,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_PER_HOST"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_PER_HOST"),
+					cli.File("/vela/parameters/hugo/external_links_per_host"),
+					cli.File("/vela/secrets/hugo/external_links_per_host"),
+				),
+			},
+			&cli.IntFlag{
+				Name:  "output.external_rate",
+				Usage: "maximum requests per second when checking external links",
+				Value: 10,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_RATE"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_RATE"),
+					cli.File("/vela/parameters/hugo/external_links_rate"),
+					cli.File("/vela/secrets/hugo/external_links_rate"),
+				),
+			},
+			&cli.IntFlag{
+				Name:  "output.external_retries",
+				Usage: "number of times to retry failed requests when checking external links",
+				Value: 2,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_RETRIES"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_RETRIES"),
+					cli.File("/vela/parameters/hugo/external_links_retries"),
+					cli.File("/vela/secrets/hugo/external_links_retries"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.external_timeout",
+				Usage: "timeout for each request when checking external links",
+				Value: "10s",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXTERNAL_LINKS_TIMEOUT"),
+					cli.EnvVar("HUGO_EXTERNAL_LINKS_TIMEOUT"),
+					cli.File("/vela/parameters/hugo/external_links_timeout"),
+					cli.File("/vela/secrets/hugo/external_links_timeout"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.html_threshold",
+				Usage: "minimum severity of the problems on the rendered pages which fails the step (supports: error, warning, none)",
+				Value: "error",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_VALIDATE_HTML_THRESHOLD"),
+					cli.EnvVar("HUGO_VALIDATE_HTML_THRESHOLD"),
+					cli.File("/vela/parameters/hugo/validate_html_threshold"),
+					cli.File("/vela/secrets/hugo/validate_html_threshold"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "output.manifest",
+				Usage: "write a manifest with the path, size, hash and content type of every file in the output directory",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MANIFEST"),
+					cli.EnvVar("HUGO_MANIFEST"),
+					cli.File("/vela/parameters/hugo/manifest"),
+					cli.File("/vela/secrets/hugo/manifest"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.manifest_path",
+				Usage: "filesystem path to write the manifest to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_MANIFEST_PATH"),
+					cli.EnvVar("HUGO_MANIFEST_PATH"),
+					cli.File("/vela/parameters/hugo/manifest_path"),
+					cli.File("/vela/secrets/hugo/manifest_path"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "output.precompress",
+				Usage: "write compressed variants of the text files in the output directory",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PRECOMPRESS"),
+					cli.EnvVar("HUGO_PRECOMPRESS"),
+					cli.File("/vela/parameters/hugo/precompress"),
+					cli.File("/vela/secrets/hugo/precompress"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "output.precompress_formats",
+				Usage: "formats to write the compressed variants in (supports: gzip, brotli)",
+				Value: []string{"gzip", "brotli"},
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PRECOMPRESS_FORMATS"),
+					cli.EnvVar("HUGO_PRECOMPRESS_FORMATS"),
+					cli.File("/vela/parameters/hugo/precompress_formats"),
+					cli.File("/vela/secrets/hugo/precompress_formats"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "output.precompress_min_size",
+				Usage: "minimum size of the files to compress, e.g. 1KB",
+				Value: "1KB",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PRECOMPRESS_MIN_SIZE"),
+					cli.EnvVar("HUGO_PRECOMPRESS_MIN_SIZE"),
+					cli.File("/vela/parameters/hugo/precompress_min_size"),
+					cli.File("/vela/secrets/hugo/precompress_min_size"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "output.validate_html",
+				Usage: "verify the rendered pages have no duplicate ids, missing titles or lang attributes, images without alt text, empty links or nested anchors",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_VALIDATE_HTML"),
+					cli.EnvVar("HUGO_VALIDATE_HTML"),
+					cli.File("/vela/parameters/hugo/validate_html"),
+					cli.File("/vela/secrets/hugo/validate_html"),
+				),
+			},
+
+			// SBOM Flags
+			&cli.BoolFlag{
+				Name:  "sbom.enabled",
+				Usage: "write a software bill of materials for the site",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SBOM"),
+					cli.EnvVar("HUGO_SBOM"),
+					cli.File("/vela/parameters/hugo/sbom"),
+					cli.File("/vela/secrets/hugo/sbom"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "sbom.format",
+				Usage: "format of the software bill of materials - options: (cyclonedx|spdx)",
+				Value: "cyclonedx",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SBOM_FORMAT"),
+					cli.EnvVar("HUGO_SBOM_FORMAT"),
+					cli.File("/vela/parameters/hugo/sbom_format"),
+					cli.File("/vela/secrets/hugo/sbom_format"),
+				),
+			},
 
 			// Theme Flags
 			&cli.StringFlag{
@@ -229,7 +663,7 @@ func main() {
 		},
 	}
 
-	err = app.Run(context.Background(), os.Args)
+	err := app.Run(context.Background(), os.Args)
 	if err != nil {
 		logrus.Fatal(err)
 	}
@@ -237,32 +671,30 @@ func main() {
 
 // run executes the plugin based off the configuration provided.
 func run(ctx context.Context, c *cli.Command) error {
-	// set the log level for the plugin
-	switch c.String("log.level") {
-	case "t", "trace", "Trace", "TRACE":
-		logrus.SetLevel(logrus.TraceLevel)
-	case "d", "debug", "Debug", "DEBUG":
-		logrus.SetLevel(logrus.DebugLevel)
-	case "w", "warn", "Warn", "WARN":
-		logrus.SetLevel(logrus.WarnLevel)
-	case "e", "error", "Error", "ERROR":
-		logrus.SetLevel(logrus.ErrorLevel)
-	case "f", "fatal", "Fatal", "FATAL":
-		logrus.SetLevel(logrus.FatalLevel)
-	case "p", "panic", "Panic", "PANIC":
-		logrus.SetLevel(logrus.PanicLevel)
-	case "i", "info", "Info", "INFO":
-		fallthrough
-	default:
-		logrus.SetLevel(logrus.InfoLevel)
+	// serialize the version information as pretty JSON
+	bytes, err := json.MarshalIndent(version.New(), "", "  ")
+	if err != nil {
+		return err
 	}
 
+	// output the version information to stdout
+	fmt.Fprintf(os.Stdout, "%s\n", string(bytes))
+
+	// set the log level for the plugin
+	setLogLevel(c.String("log.level"))
+
 	logrus.WithFields(logrus.Fields{
 		"code":     "https://github.com/go-vela/vela-hugo",
 		"docs":     "https://go-vela.github.io/docs/plugins/registry/pipeline/hugo",
 		"registry": "https://hub.docker.com/r/target/vela-hugo",
 	}).Info("Vela Hugo Plugin")
 
+	// validate the parameters against the schema for the plugin
+	err = validateParameters(parameterSchema(c.Flags), os.Environ(), c.Bool("hugo.strict_parameters"))
+	if err != nil {
+		return tableErr(err)
+	}
+
 	// capture extended binary configuration
 	//
 	// extended binary includes more features and functionality
@@ -270,22 +702,56 @@ func run(ctx context.Context, c *cli.Command) error {
 	// capture custom hugo version requested
 	version := c.String("hugo.version")
 
+	// capture whether the plugin should only print the commands to run
+	dryRun := c.Bool("hugo.dry_run")
+
 	// check if we should fetch extended binary or custom hugo version
 	if len(version) > 0 || extended {
-		// attempt to install the custom hugo version
-		err := install(ctx, extended, version, os.Getenv("PLUGIN_HUGO_VERSION"))
-		if err != nil {
-			return err
+		// check if the download should only be printed
+		if dryRun {
+			url, err := downloadURL(extended, version, os.Getenv("PLUGIN_HUGO_VERSION"))
+			if err != nil {
+				return err
+			}
+
+			if len(url) > 0 {
+				fmt.Printf("# download hugo from %s\n", url)
+			}
+		} else {
+			// attempt to install the custom hugo version
+			err := install(ctx, extended, version, os.Getenv("PLUGIN_HUGO_VERSION"))
+			if err != nil {
+				return err
+			}
 		}
 	}
 
+	// capture the site params to override
+	params, err := parseObject("params", c.String("config.params"))
+	if err != nil {
+		return err
+	}
+
+	// capture the site configuration to override
+	overrides, err := parseObject("config_overrides", c.String("config.overrides"))
+	if err != nil {
+		return err
+	}
+
 	// create the plugin
 	p := &Plugin{
+		Artifact: &Artifact{
+			Enabled: c.Bool("artifact.enabled"),
+			Format:  c.String("artifact.format"),
+			Path:    c.String("artifact.path"),
+		},
 		Build: &Build{
-			BaseURL: c.String("build.base_url"),
-			Draft:   c.Bool("build.draft"),
-			Expired: c.Bool("build.expired"),
-			Future:  c.Bool("build.future"),
+			BaseURL:       c.String("build.base_url"),
+			AllowRelative: c.Bool("build.allow_relative_base_url"),
+			Draft:         c.Bool("build.draft"),
+			Expired:       c.Bool("build.expired"),
+			Future:        c.Bool("build.future"),
+			Reproducible:  c.Bool("build.reproducible"),
 		},
 		Config: &Config{
 			CacheDirectory:   c.String("config.cache_directory"),
@@ -296,6 +762,48 @@ func run(ctx context.Context, c *cli.Command) error {
 			LayoutDirectory:  c.String("config.layout_directory"),
 			OutputDirectory:  c.String("config.output_directory"),
 			SourceDirectory:  c.String("config.source_directory"),
+			Params:           params,
+			Overrides:        overrides,
+			ValidateSite:     c.Bool("config.validate_site"),
+			Print:            c.Bool("config.print"),
+			PrintFile:        c.String("config.print_file"),
+			Baseline:         c.String("config.baseline"),
+		},
+		Module: &Module{
+			Report:             c.Bool("module.report"),
+			AllowedLicenses:    c.StringSlice("module.allowed_licenses"),
+			DisallowedLicenses: c.StringSlice("module.disallowed_licenses"),
+			RequirePinned:      c.Bool("module.require_pinned"),
+		},
+		NPM: &NPM{
+			Install:        c.Bool("npm.install"),
+			Command:        c.String("npm.command"),
+			CacheDirectory: c.String("npm.cache_directory"),
+		},
+		Output: &Output{
+			Baseline:           c.String("output.baseline"),
+			Budgets:            c.StringSlice("output.budgets"),
+			CheckExternalLinks: c.Bool("output.check_external_links"),
+			CheckLinks:         c.Bool("output.check_links"),
+			ExternalIgnore:     c.StringSlice("output.external_ignore"),
+			ExternalInclude:    c.StringSlice("output.external_include"),
+			ExternalPerHost:    c.Int("output.external_per_host"),
+			ExternalRate:       c.Int("output.external_rate"),
+			ExternalRetries:    c.Int("output.external_retries"),
+			ExternalTimeout:    c.String("output.external_timeout"),
+			HTMLThreshold:      c.String("output.html_threshold"),
+			FileBudget:         c.String("output.budget_file_size"),
+			TotalBudget:        c.String("output.budget_total_size"),
+			Manifest:           c.Bool("output.manifest"),
+			ManifestPath:       c.String("output.manifest_path"),
+			Precompress:        c.Bool("output.precompress"),
+			PrecompressFormats: c.StringSlice("output.precompress_formats"),
+			PrecompressMinSize: c.String("output.precompress_min_size"),
+			ValidateHTML:       c.Bool("output.validate_html"),
+		},
+		SBOM: &SBOM{
+			Enabled: c.Bool("sbom.enabled"),
+			Format:  c.String("sbom.format"),
 		},
 		Theme: &Theme{
 			Name:      c.String("theme.name"),
@@ -304,11 +812,38 @@ func run(ctx context.Context, c *cli.Command) error {
 	}
 
 	// validate the plugin
-	err := p.Validate()
+	err = p.Validate()
 	if err != nil {
-		return err
+		return tableErr(err)
+	}
+
+	// check if the plugin should only print the commands to run
+	if dryRun {
+		return p.DryRun(ctx)
 	}
 
 	// execute the plugin
 	return p.Exec(ctx)
 }
+
+// setLogLevel sets the log level for the plugin.
+func setLogLevel(level string) {
+	switch level {
+	case "t", "trace", "Trace", "TRACE":
+		logrus.SetLevel(logrus.TraceLevel)
+	case "d", "debug", "Debug", "DEBUG":
+		logrus.SetLevel(logrus.DebugLevel)
+	case "w", "warn", "Warn", "WARN":
+		logrus.SetLevel(logrus.WarnLevel)
+	case "e", "error", "Error", "ERROR":
+		logrus.SetLevel(logrus.ErrorLevel)
+	case "f", "fatal", "Fatal", "FATAL":
+		logrus.SetLevel(logrus.FatalLevel)
+	case "p", "panic", "Panic", "PANIC":
+		logrus.SetLevel(logrus.PanicLevel)
+	case "i", "info", "Info", "INFO":
+		fallthrough
+	default:
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// name of the file the manifest is written to.
+const _manifestFile = "manifest.json"
+
+// Manifest represents every file in the output directory of the site.
+type Manifest struct {
+	// number of files in the output directory
+	Count int `json:"count"`
+	// total size in bytes of the files in the output directory
+	Size int64 `json:"size"`
+	// files in the output directory sorted by path
+	Files []*ManifestFile `json:"files"`
+}
+
+// ManifestFile represents a file in the output directory of the site.
+type ManifestFile struct {
+	// slash separated path relative to the output directory
+	Path string `json:"path"`
+	// size in bytes of the file
+	Size int64 `json:"size"`
+	// hex encoded SHA256 of the file
+	SHA256 string `json:"sha256"`
+	// media type the file is served with
+	ContentType string `json:"content_type"`
+}
+
+// buildManifest captures the path, size, hash and content type for every file in the directory.
+func buildManifest(dir string) (*Manifest, error) {
+	m := &Manifest{Files: []*ManifestFile{}}
+
+	// walk visits the files in lexical order which keeps the manifest deterministic
+	err := afero.Walk(appFS, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := manifestFile(path)
+		if err != nil {
+			return err
+		}
+
+		file.Path = filepath.ToSlash(rel)
+
+		m.Files = append(m.Files, file)
+		m.Count++
+		m.Size += file.Size
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to build manifest for %s: %w", dir, err)
+	}
+
+	return m, nil
+}
+
+// manifestFile reads the size, hash and content type for the file.
+func manifestFile(path string) (*ManifestFile, error) {
+	file, err := appFS.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	// capture the start of the file to detect the content type
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write(head[:n])
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if len(contentType) == 0 {
+		contentType = http.DetectContentType(head[:n])
+	}
+
+	return &ManifestFile{
+		Size:        int64(n) + size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		ContentType: contentType,
+	}, nil
+}
+
+// Write outputs the manifest to the provided path.
+func (m *Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("writing manifest of %d file(s) to %s", m.Count, path)
+
+	return afero.WriteFile(appFS, path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func sum(content string) string {
+	hash := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func TestBuildManifest(t *testing.T) {
+	// setup filesystem
+	setupSite(t)
+
+	want := &Manifest{
+		Count: 3,
+		Size:  37,
+		Files: []*ManifestFile{
+			{Path: "css/main.css", Size: 7, SHA256: sum("body {}"), ContentType: "text/css; charset=utf-8"},
+			{Path: "docs/index.html", Size: 17, SHA256: sum("<html>docs</html>"), ContentType: "text/html; charset=utf-8"},
+			{Path: "index.html", Size: 13, SHA256: sum("<html></html>"), ContentType: "text/html; charset=utf-8"},
+		},
+	}
+
+	got, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildManifest is %+v, want %+v", got, want)
+	}
+}
+
+func TestManifestFile(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	// setup tests
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{
+			name:    "content type from extension",
+			path:    "/site/public/feed.json",
+			content: `{"items": []}`,
+			want:    "application/json",
+		},
+		{
+			name:    "content type from content",
+			path:    "/site/public/_redirects",
+			content: "/old /new 301",
+			want:    "text/plain; charset=utf-8",
+		},
+		{
+			name:    "empty file",
+			path:    "/site/public/CNAME",
+			content: "",
+			want:    "text/plain; charset=utf-8",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := a.WriteFile(test.path, []byte(test.content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", test.path, err)
+		}
+
+		got, err := manifestFile(test.path)
+		if err != nil {
+			t.Errorf("%s manifestFile returned err: %v", test.name, err)
+
+			continue
+		}
+
+		if got.ContentType != test.want {
+			t.Errorf("%s content type is %s, want %s", test.name, got.ContentType, test.want)
+		}
+
+		if got.Size != int64(len(test.content)) {
+			t.Errorf("%s size is %d, want %d", test.name, got.Size, len(test.content))
+		}
+
+		if got.SHA256 != sum(test.content) {
+			t.Errorf("%s sha256 is %s, want %s", test.name, got.SHA256, sum(test.content))
+		}
+	}
+}
+
+func TestOutput_Exec_Manifest(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	// setup types
+	o := &Output{Manifest: true}
+
+	err := o.Exec(t.Context(), "/site/public", "/site", "")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	data, err := a.ReadFile("/site/manifest.json")
+	if err != nil {
+		t.Fatalf("unable to read manifest: %v", err)
+	}
+
+	got := new(Manifest)
+
+	err = json.Unmarshal(data, got)
+	if err != nil {
+		t.Fatalf("unable to parse manifest: %v", err)
+	}
+
+	want, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("manifest is %+v, want %+v", got, want)
+	}
+}
+
+func TestOutput_manifestPath(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		output Output
+		want   string
+	}{
+		{
+			output: Output{Manifest: true},
+			want:   "/site/manifest.json",
+		},
+		{
+			output: Output{Manifest: true, ManifestPath: "/reports/files.json"},
+			want:   "/reports/files.json",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.output.manifestPath("/site")
+
+		if got != test.want {
+			t.Errorf("manifestPath is %s, want %s", got, test.want)
+		}
+	}
+}
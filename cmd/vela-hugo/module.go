@@ -0,0 +1,503 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// name of the JSON report for the hugo modules.
+	_moduleReportJSON = "hugo-modules.json"
+	// name of the markdown report for the hugo modules.
+	_moduleReportMarkdown = "hugo-modules.md"
+	// license used when no license could be detected.
+	_noAssertion = "NOASSERTION"
+)
+
+// pseudoVersion matches a Go module pseudo-version which points to a commit.
+//
+// https://go.dev/ref/mod#pseudo-versions
+var pseudoVersion = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// licenses contains the phrases used to detect the SPDX identifier
+// of a license file, ordered from most to least specific.
+var licenses = []struct {
+	id      string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3, 29 june 2007"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2, june 1991"}},
+	{"MPL-2.0", []string{"mozilla public license", "version 2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"CC-BY-4.0", []string{"creative commons attribution 4.0"}},
+}
+
+// Module represents the plugin configuration for Hugo Modules information.
+type Module struct {
+	// write a report of the Hugo Modules used by the site
+	Report bool
+	// licenses allowed for Hugo Modules used by the site
+	AllowedLicenses []string
+	// licenses disallowed for Hugo Modules used by the site
+	DisallowedLicenses []string
+	// require Hugo Modules to be pinned to a released version
+	RequirePinned bool
+}
+
+// ModuleNode represents a Hugo Module in the dependency tree of the site.
+type ModuleNode struct {
+	Path         string        `json:"path"`
+	Version      string        `json:"version,omitempty"`
+	Replace      string        `json:"replace,omitempty"`
+	Disabled     bool          `json:"disabled,omitempty"`
+	License      string        `json:"license,omitempty"`
+	LicenseFile  string        `json:"license_file,omitempty"`
+	Dependencies []*ModuleNode `json:"dependencies,omitempty"`
+}
+
+// ModuleReport represents the report of Hugo Modules used by the site.
+type ModuleReport struct {
+	Project    string        `json:"project"`
+	Modules    []*ModuleNode `json:"modules"`
+	Violations []string      `json:"violations,omitempty"`
+}
+
+// moduleMount represents the subset of a module
+// returned from the `hugo config mounts` command.
+type moduleMount struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Dir     string `json:"dir"`
+}
+
+// Enabled returns true if the Hugo Modules should be audited.
+func (m *Module) Enabled() bool {
+	return m.Report || m.RequirePinned || len(m.AllowedLicenses) > 0 || len(m.DisallowedLicenses) > 0
+}
+
+// Audit parses the output from the `hugo mod graph` and `hugo config mounts`
+// commands into a report and checks it against the configured policy.
+func (m *Module) Audit(graph, mounts []byte) (*ModuleReport, error) {
+	logrus.Trace("auditing hugo modules")
+
+	// capture the directories for the hugo modules
+	dirs, err := parseMounts(mounts)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the dependency tree for the hugo modules
+	report, err := parseGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	// detect the licenses for the hugo modules
+	walkModules(report.Modules, func(node *ModuleNode) {
+		node.LicenseFile, node.License = detectLicense(dirs[node.Path])
+	})
+
+	// check the hugo modules against the policy
+	report.Violations = m.violations(report)
+
+	return report, nil
+}
+
+// violations returns the list of Hugo Modules that don't satisfy the policy.
+func (m *Module) violations(report *ModuleReport) []string {
+	var violations []string
+
+	// track the modules already checked
+	seen := make(map[string]bool)
+
+	walkModules(report.Modules, func(node *ModuleNode) {
+		if seen[node.Path] || node.Disabled {
+			return
+		}
+
+		seen[node.Path] = true
+
+		// check if the license is disallowed
+		if containsFold(m.DisallowedLicenses, node.License) {
+			violations = append(violations, fmt.Sprintf("%s uses disallowed license %s", node.Path, node.License))
+		}
+
+		// check if the license is not allowed
+		if len(m.AllowedLicenses) > 0 && !containsFold(m.AllowedLicenses, node.License) {
+			violations = append(violations, fmt.Sprintf("%s uses license %s which is not allowed", node.Path, node.License))
+		}
+
+		// check if the module is pinned to a released version
+		if m.RequirePinned && !node.pinned() {
+			violations = append(violations, fmt.Sprintf("%s is not pinned to a released version", node.Path))
+		}
+	})
+
+	return violations
+}
+
+// pinned returns true if the module resolves to a released version.
+func (n *ModuleNode) pinned() bool {
+	version := n.Version
+
+	// check if the module is replaced
+	if len(n.Replace) > 0 {
+		// replacements without a version point to a directory
+		_, version, _ = strings.Cut(n.Replace, "@")
+	}
+
+	return len(version) > 0 && !pseudoVersion.MatchString(version)
+}
+
+// Write outputs the report in JSON and markdown format to the provided directory.
+func (r *ModuleReport) Write(dir string) error {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// serialize the report as pretty JSON
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, _moduleReportJSON)
+
+	logrus.Infof("writing hugo modules report to %s", path)
+
+	err = a.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	path = filepath.Join(dir, _moduleReportMarkdown)
+
+	logrus.Infof("writing hugo modules report to %s", path)
+
+	return a.WriteFile(path, []byte(r.Markdown()), 0644)
+}
+
+// Markdown formats the report as a markdown document.
+func (r *ModuleReport) Markdown() string {
+	b := new(strings.Builder)
+
+	fmt.Fprintf(b, "# Hugo Modules for %s\n\n", r.Project)
+
+	// check if no modules are used
+	if len(r.Modules) == 0 {
+		b.WriteString("No Hugo Modules are used.\n")
+
+		return b.String()
+	}
+
+	b.WriteString("| Module | Version | License | License File |\n")
+	b.WriteString("| ------ | ------- | ------- | ------------ |\n")
+
+	seen := make(map[string]bool)
+
+	walkModules(r.Modules, func(node *ModuleNode) {
+		if seen[node.Path] {
+			return
+		}
+
+		seen[node.Path] = true
+
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s |\n", node.Path, orNone(node.Version), node.License, orNone(node.LicenseFile))
+	})
+
+	b.WriteString("\n## Dependency Tree\n\n")
+
+	writeTree(b, r.Modules, 0)
+
+	// check if any violations were found
+	if len(r.Violations) > 0 {
+		b.WriteString("\n## Violations\n\n")
+
+		for _, violation := range r.Violations {
+			fmt.Fprintf(b, "- %s\n", violation)
+		}
+	}
+
+	return b.String()
+}
+
+// Exec writes the report for the Hugo Modules to the
+// provided directory and verifies the policy was satisfied.
+func (m *Module) Exec(report *ModuleReport, dir string) error {
+	logrus.Debug("auditing hugo modules")
+
+	// check if the report should be written
+	if m.Report {
+		err := report.Write(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the policy was violated
+	if len(report.Violations) > 0 {
+		return fmt.Errorf("hugo modules violate policy:\n  %s", strings.Join(report.Violations, "\n  "))
+	}
+
+	return nil
+}
+
+// modules captures the Hugo Modules used by the site.
+func (p *Plugin) modules(ctx context.Context) (*ModuleReport, error) {
+	// capture the dependency graph for the hugo modules
+	graph, err := outputCmd(p.hugoCmd(ctx, "mod", "graph"))
+	if err != nil {
+		return nil, err
+	}
+
+	// capture the directories for the hugo modules
+	mounts, err := outputCmd(p.hugoCmd(ctx, "config", "mounts"))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Module.Audit(graph, mounts)
+}
+
+// parseGraph parses the output from the `hugo mod graph`
+// command into a dependency tree for the project.
+func parseGraph(graph []byte) (*ModuleReport, error) {
+	// track the modules and who requires them
+	nodes := make(map[string]*ModuleNode)
+	edges := make(map[string][]string)
+	required := make(map[string]bool)
+
+	var owners []string
+
+	for _, line := range strings.Split(string(graph), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		// check if the module is disabled
+		line, disabled := strings.CutPrefix(line, "DISABLED ")
+
+		// check if the module is replaced
+		line, replace, _ := strings.Cut(line, " => ")
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("unable to parse hugo module graph line: %s", line)
+		}
+
+		owner, _, _ := strings.Cut(fields[0], "@")
+		path, version, _ := strings.Cut(fields[1], "@")
+
+		nodes[path] = &ModuleNode{
+			Path:     path,
+			Version:  version,
+			Replace:  strings.TrimSpace(replace),
+			Disabled: disabled,
+		}
+
+		// check if the owner was seen before
+		if _, ok := edges[owner]; !ok {
+			owners = append(owners, owner)
+		}
+
+		edges[owner] = append(edges[owner], path)
+		required[path] = true
+	}
+
+	report := new(ModuleReport)
+
+	// the project is the only owner not required by another module
+	for _, owner := range owners {
+		if !required[owner] {
+			report.Project = owner
+
+			break
+		}
+	}
+
+	report.Modules = buildTree(report.Project, nodes, edges, map[string]bool{report.Project: true})
+
+	return report, nil
+}
+
+// buildTree creates the dependency tree for the owner,
+// skipping modules already present in the branch.
+func buildTree(owner string, nodes map[string]*ModuleNode, edges map[string][]string, branch map[string]bool) []*ModuleNode {
+	var tree []*ModuleNode
+
+	for _, path := range edges[owner] {
+		// prevent cycles in the dependency graph
+		if branch[path] {
+			continue
+		}
+
+		node := *nodes[path]
+
+		branch[path] = true
+		node.Dependencies = buildTree(path, nodes, edges, branch)
+		delete(branch, path)
+
+		tree = append(tree, &node)
+	}
+
+	return tree
+}
+
+// parseMounts parses the output from the `hugo config mounts`
+// command into the directories for each module.
+func parseMounts(mounts []byte) (map[string]string, error) {
+	dirs := make(map[string]string)
+
+	decoder := json.NewDecoder(bytes.NewReader(mounts))
+
+	for {
+		m := new(moduleMount)
+
+		err := decoder.Decode(m)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse hugo module mounts: %w", err)
+		}
+
+		dirs[m.Path] = m.Dir
+	}
+
+	return dirs, nil
+}
+
+// detectLicense returns the license file and SPDX
+// identifier for the license found in the directory.
+func detectLicense(dir string) (string, string) {
+	// check if the module has a directory
+	if len(dir) == 0 {
+		return "", _noAssertion
+	}
+
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	infos, err := a.ReadDir(dir)
+	if err != nil {
+		logrus.Warnf("unable to read module directory %s: %v", dir, err)
+
+		return "", _noAssertion
+	}
+
+	for _, info := range infos {
+		name := strings.ToLower(info.Name())
+
+		// check if the file is a license file
+		if info.IsDir() || !(strings.HasPrefix(name, "license") ||
+			strings.HasPrefix(name, "licence") ||
+			strings.HasPrefix(name, "copying")) {
+			continue
+		}
+
+		data, err := a.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			logrus.Warnf("unable to read license file %s: %v", info.Name(), err)
+
+			continue
+		}
+
+		return info.Name(), identifyLicense(data)
+	}
+
+	return "", _noAssertion
+}
+
+// identifyLicense returns the SPDX identifier for the license text.
+func identifyLicense(data []byte) string {
+	// normalize the casing and whitespace of the text
+	text := strings.Join(strings.Fields(strings.ToLower(string(data))), " ")
+
+	for _, license := range licenses {
+		found := true
+
+		for _, phrase := range license.phrases {
+			if !strings.Contains(text, phrase) {
+				found = false
+
+				break
+			}
+		}
+
+		if found {
+			return license.id
+		}
+	}
+
+	return _noAssertion
+}
+
+// walkModules calls the function for every module in the tree.
+func walkModules(tree []*ModuleNode, fn func(*ModuleNode)) {
+	for _, node := range tree {
+		fn(node)
+
+		walkModules(node.Dependencies, fn)
+	}
+}
+
+// writeTree writes the dependency tree as a nested markdown list.
+func writeTree(b *strings.Builder, tree []*ModuleNode, depth int) {
+	for _, node := range tree {
+		name := node.Path
+
+		if len(node.Version) > 0 {
+			name = fmt.Sprintf("%s@%s", node.Path, node.Version)
+		}
+
+		fmt.Fprintf(b, "%s- `%s` (%s)\n", strings.Repeat("  ", depth), name, node.License)
+
+		writeTree(b, node.Dependencies, depth+1)
+	}
+}
+
+// containsFold returns true if the value is in the list, ignoring case.
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orNone returns a placeholder for empty values in markdown.
+func orNone(value string) string {
+	if len(value) == 0 {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	testGraph = `github.com/example/site github.com/example/theme@v1.2.0
+github.com/example/theme@v1.2.0 github.com/example/shortcodes@v0.0.0-20240101120000-abcdef123456
+github.com/example/site github.com/example/local => ../local
+DISABLED github.com/example/site github.com/example/unused@v0.1.0
+`
+	testMounts = `{
+  "path": "github.com/example/site",
+  "version": "",
+  "dir": "/site/"
+}
+{
+  "path": "github.com/example/theme",
+  "version": "v1.2.0",
+  "dir": "/cache/theme@v1.2.0/"
+}
+{
+  "path": "github.com/example/shortcodes",
+  "version": "v0.0.0-20240101120000-abcdef123456",
+  "dir": "/cache/shortcodes/"
+}
+`
+)
+
+func TestModule_Audit(t *testing.T) {
+	// setup filesystem
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/cache/theme@v1.2.0/LICENSE", []byte("MIT License\n\nPermission is hereby granted, free of charge, to any person"), 0644)
+	if err != nil {
+		t.Errorf("unable to create license: %v", err)
+	}
+
+	err = a.WriteFile("/cache/shortcodes/COPYING", []byte("GNU GENERAL PUBLIC LICENSE\n  Version 3, 29 June 2007"), 0644)
+	if err != nil {
+		t.Errorf("unable to create license: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		module Module
+		want   []string
+	}{
+		{
+			name:   "report without policy",
+			module: Module{Report: true},
+			want:   nil,
+		},
+		{
+			name:   "disallowed license",
+			module: Module{DisallowedLicenses: []string{"gpl-3.0"}},
+			want: []string{
+				"github.com/example/shortcodes uses disallowed license GPL-3.0",
+			},
+		},
+		{
+			name:   "allowed licenses",
+			module: Module{AllowedLicenses: []string{"MIT", "Apache-2.0"}},
+			want: []string{
+				"github.com/example/shortcodes uses license GPL-3.0 which is not allowed",
+				"github.com/example/local uses license NOASSERTION which is not allowed",
+			},
+		},
+		{
+			name:   "require pinned",
+			module: Module{RequirePinned: true},
+			want: []string{
+				"github.com/example/shortcodes is not pinned to a released version",
+				"github.com/example/local is not pinned to a released version",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.module.Audit([]byte(testGraph), []byte(testMounts))
+		if err != nil {
+			t.Errorf("%s Audit returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got.Violations, test.want) {
+			t.Errorf("%s Audit violations is %v, want %v", test.name, got.Violations, test.want)
+		}
+	}
+}
+
+func TestModule_parseGraph(t *testing.T) {
+	got, err := parseGraph([]byte(testGraph))
+	if err != nil {
+		t.Errorf("parseGraph returned err: %v", err)
+	}
+
+	if got.Project != "github.com/example/site" {
+		t.Errorf("parseGraph project is %s, want %s", got.Project, "github.com/example/site")
+	}
+
+	if len(got.Modules) != 3 {
+		t.Errorf("parseGraph returned %d modules, want %d", len(got.Modules), 3)
+	}
+
+	theme := got.Modules[0]
+
+	if theme.Version != "v1.2.0" || len(theme.Dependencies) != 1 {
+		t.Errorf("parseGraph theme is %+v", theme)
+	}
+
+	if got.Modules[1].Replace != "../local" {
+		t.Errorf("parseGraph replace is %s, want %s", got.Modules[1].Replace, "../local")
+	}
+
+	if !got.Modules[2].Disabled {
+		t.Errorf("parseGraph should have disabled %s", got.Modules[2].Path)
+	}
+
+	_, err = parseGraph([]byte("not a graph line"))
+	if err == nil {
+		t.Errorf("parseGraph should have returned err")
+	}
+}
+
+func TestModule_identifyLicense(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Apache License\n  Version 2.0, January 2004", "Apache-2.0"},
+		{"GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007 GNU General Public License", "LGPL-3.0"},
+		{"Redistribution and use in source and binary forms ... Neither the name of", "BSD-3-Clause"},
+		{"Mozilla Public License Version 2.0", "MPL-2.0"},
+		{"All rights reserved.", _noAssertion},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := identifyLicense([]byte(test.text))
+
+		if got != test.want {
+			t.Errorf("identifyLicense is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestModuleReport_Write(t *testing.T) {
+	// setup filesystem
+	appFS = afero.NewMemMapFs()
+
+	report, err := parseGraph([]byte(testGraph))
+	if err != nil {
+		t.Errorf("parseGraph returned err: %v", err)
+	}
+
+	err = report.Write("/site")
+	if err != nil {
+		t.Errorf("Write returned err: %v", err)
+	}
+
+	a := &afero.Afero{Fs: appFS}
+
+	for _, name := range []string{_moduleReportJSON, _moduleReportMarkdown} {
+		data, err := a.ReadFile(filepath.Join("/site", name))
+		if err != nil {
+			t.Errorf("unable to read %s: %v", name, err)
+		}
+
+		if !strings.Contains(string(data), "github.com/example/shortcodes") {
+			t.Errorf("%s is missing module: %s", name, data)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// name of the npm binary.
+	_npm = "npm"
+	// name of the npm package manifest.
+	_packageJSON = "package.json"
+	// name of the npm package lockfile.
+	_packageLock = "package-lock.json"
+	// name of the npm shrinkwrap lockfile.
+	_shrinkwrap = "npm-shrinkwrap.json"
+)
+
+// NPM represents the plugin configuration for installing npm packages.
+type NPM struct {
+	// install the npm packages for the site before building it
+	Install bool
+	// command used to install the npm packages
+	Command string
+	// filesystem path to the npm cache directory
+	CacheDirectory string
+}
+
+// Exec installs the npm packages for the site in the provided directory.
+func (n *NPM) Exec(ctx context.Context, dir string) error {
+	logrus.Debug("installing npm packages")
+
+	cmd, err := n.command(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	// install the npm packages for the site
+	err = execCmd(cmd)
+	if err != nil {
+		return fmt.Errorf("npm install failed, no hugo build was attempted: %w", err)
+	}
+
+	return nil
+}
+
+// command formats the command to install the npm packages
+// based off the package files found in the directory.
+func (n *NPM) command(ctx context.Context, dir string) (*exec.Cmd, error) {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// check if a package manifest exists for the site
+	exists, err := a.Exists(filepath.Join(dir, _packageJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("no %s found @ %s for npm install", _packageJSON, filepath.Join(dir, _packageJSON))
+	}
+
+	// use the custom command when provided
+	args := strings.Fields(n.Command)
+
+	if len(args) == 0 {
+		// install the packages exactly as they are locked
+		args = []string{_npm, "ci"}
+
+		// check if a lockfile exists for the site
+		locked := false
+
+		for _, name := range []string{_packageLock, _shrinkwrap} {
+			exists, err = a.Exists(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+
+			locked = locked || exists
+		}
+
+		if !locked {
+			logrus.Warnf("no %s found - falling back to npm install", _packageLock)
+
+			args = []string{_npm, "install"}
+		}
+	}
+
+	//nolint:gosec // command is provided by the pipeline author
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+
+	// check if a cache directory is provided
+	if len(n.CacheDirectory) > 0 {
+		// npm runs from the site so the cache must be absolute
+		cache, err := filepath.Abs(n.CacheDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		cmd.Env = append(os.Environ(), fmt.Sprintf("npm_config_cache=%s", cache))
+	}
+
+	return cmd, nil
+}
+
+// npmPackage represents an npm package used by the site.
+type npmPackage struct {
+	Name      string
+	Version   string
+	License   string
+	Resolved  string
+	Integrity string
+	Dev       bool
+}
+
+// packageManifest represents the subset of a package.json used by the plugin.
+type packageManifest struct {
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// packageLockfile represents the subset of a package-lock.json used by the plugin.
+type packageLockfile struct {
+	LockfileVersion int                          `json:"lockfileVersion"`
+	Packages        map[string]*packageLockEntry `json:"packages"`
+	Dependencies    map[string]*packageLockEntry `json:"dependencies"`
+}
+
+// packageLockEntry represents a package in a package-lock.json.
+type packageLockEntry struct {
+	Version      string                       `json:"version"`
+	Resolved     string                       `json:"resolved"`
+	Integrity    string                       `json:"integrity"`
+	License      any                          `json:"license"`
+	Dev          bool                         `json:"dev"`
+	Link         bool                         `json:"link"`
+	Dependencies map[string]*packageLockEntry `json:"dependencies"`
+}
+
+// readPackages returns the npm packages used by the site in the directory,
+// preferring the resolved versions from the lockfile when it exists.
+func readPackages(dir string) ([]*npmPackage, error) {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// check if a lockfile exists for the site
+	data, err := a.ReadFile(filepath.Join(dir, _packageLock))
+	if err == nil {
+		lockfile := new(packageLockfile)
+
+		err = json.Unmarshal(data, lockfile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", _packageLock, err)
+		}
+
+		return lockfile.packages(), nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// check if a package manifest exists for the site
+	data, err = a.ReadFile(filepath.Join(dir, _packageJSON))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	manifest := new(packageManifest)
+
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", _packageJSON, err)
+	}
+
+	var packages []*npmPackage
+
+	for _, name := range sortedKeys(manifest.Dependencies) {
+		packages = append(packages, &npmPackage{Name: name, Version: manifest.Dependencies[name]})
+	}
+
+	for _, name := range sortedKeys(manifest.DevDependencies) {
+		packages = append(packages, &npmPackage{Name: name, Version: manifest.DevDependencies[name], Dev: true})
+	}
+
+	return packages, nil
+}
+
+// packages returns the installed packages from the lockfile.
+func (l *packageLockfile) packages() []*npmPackage {
+	var packages []*npmPackage
+
+	// lockfile version 2 and 3 list every package by its install path
+	if len(l.Packages) > 0 {
+		for _, path := range sortedKeys(l.Packages) {
+			entry := l.Packages[path]
+
+			// skip the root project and linked workspaces
+			if len(path) == 0 || entry.Link || !strings.Contains(path, "node_modules/") {
+				continue
+			}
+
+			name := path[strings.LastIndex(path, "node_modules/")+len("node_modules/"):]
+
+			packages = append(packages, entry.npmPackage(name))
+		}
+
+		return packages
+	}
+
+	// lockfile version 1 nests the dependencies of each package
+	var walk func(map[string]*packageLockEntry)
+
+	walk = func(deps map[string]*packageLockEntry) {
+		for _, name := range sortedKeys(deps) {
+			packages = append(packages, deps[name].npmPackage(name))
+
+			walk(deps[name].Dependencies)
+		}
+	}
+
+	walk(l.Dependencies)
+
+	return packages
+}
+
+// npmPackage converts the lockfile entry to an npm package.
+func (e *packageLockEntry) npmPackage(name string) *npmPackage {
+	pkg := &npmPackage{
+		Name:      name,
+		Version:   e.Version,
+		Resolved:  e.Resolved,
+		Integrity: e.Integrity,
+		Dev:       e.Dev,
+	}
+
+	// the license is either a string or a legacy object
+	switch license := e.License.(type) {
+	case string:
+		pkg.License = license
+	case map[string]any:
+		pkg.License, _ = license["type"].(string)
+	}
+
+	return pkg
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
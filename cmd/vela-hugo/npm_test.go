@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_readPackages(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []*npmPackage
+	}{
+		{
+			name:  "no package manifest",
+			files: map[string]string{},
+			want:  nil,
+		},
+		{
+			name: "package manifest",
+			files: map[string]string{
+				"/site/package.json": `{"dependencies": {"postcss": "^8.4.0"}, "devDependencies": {"@tailwindcss/cli": "^4.0.0"}}`,
+			},
+			want: []*npmPackage{
+				{Name: "postcss", Version: "^8.4.0"},
+				{Name: "@tailwindcss/cli", Version: "^4.0.0", Dev: true},
+			},
+		},
+		{
+			name: "package lockfile v3",
+			files: map[string]string{
+				"/site/package.json": `{"dependencies": {"postcss": "^8.4.0"}}`,
+				"/site/package-lock.json": `{"lockfileVersion": 3, "packages": {
+					"": {"name": "site"},
+					"node_modules/postcss": {"version": "8.4.31", "resolved": "https://registry.npmjs.org/postcss/-/postcss-8.4.31.tgz", "integrity": "sha512-abc", "license": "MIT"},
+					"node_modules/postcss/node_modules/nanoid": {"version": "3.3.6", "license": {"type": "MIT"}, "dev": true}
+				}}`,
+			},
+			want: []*npmPackage{
+				{Name: "postcss", Version: "8.4.31", License: "MIT", Resolved: "https://registry.npmjs.org/postcss/-/postcss-8.4.31.tgz", Integrity: "sha512-abc"},
+				{Name: "nanoid", Version: "3.3.6", License: "MIT", Dev: true},
+			},
+		},
+		{
+			name: "package lockfile v1",
+			files: map[string]string{
+				"/site/package-lock.json": `{"lockfileVersion": 1, "dependencies": {
+					"postcss": {"version": "8.4.31", "dependencies": {"nanoid": {"version": "3.3.6"}}}
+				}}`,
+			},
+			want: []*npmPackage{
+				{Name: "postcss", Version: "8.4.31"},
+				{Name: "nanoid", Version: "3.3.6"},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		a := &afero.Afero{Fs: appFS}
+
+		for path, content := range test.files {
+			err := a.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				t.Errorf("unable to create file %s: %v", path, err)
+			}
+		}
+
+		got, err := readPackages("/site")
+		if err != nil {
+			t.Errorf("%s readPackages returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s readPackages is %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNPM_command(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		npm     NPM
+		files   []string
+		want    []string
+	}{
+		{
+			failure: true,
+			name:    "no package manifest",
+			npm:     NPM{Install: true},
+			files:   []string{},
+		},
+		{
+			failure: false,
+			name:    "package manifest with lockfile",
+			npm:     NPM{Install: true},
+			files:   []string{"/site/package.json", "/site/package-lock.json"},
+			want:    []string{"npm", "ci"},
+		},
+		{
+			failure: false,
+			name:    "package manifest with shrinkwrap",
+			npm:     NPM{Install: true},
+			files:   []string{"/site/package.json", "/site/npm-shrinkwrap.json"},
+			want:    []string{"npm", "ci"},
+		},
+		{
+			failure: false,
+			name:    "package manifest without lockfile",
+			npm:     NPM{Install: true},
+			files:   []string{"/site/package.json"},
+			want:    []string{"npm", "install"},
+		},
+		{
+			failure: false,
+			name:    "custom command",
+			npm:     NPM{Install: true, Command: "npm ci --omit=optional", CacheDirectory: "/cache"},
+			files:   []string{"/site/package.json", "/site/package-lock.json"},
+			want:    []string{"npm", "ci", "--omit=optional"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		for _, path := range test.files {
+			_, err := appFS.Create(path)
+			if err != nil {
+				t.Errorf("unable to create file %s: %v", path, err)
+			}
+		}
+
+		got, err := test.npm.command(t.Context(), "/site")
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s command should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s command returned err: %v", test.name, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got.Args, test.want) {
+			t.Errorf("%s command is %v, want %v", test.name, got.Args, test.want)
+		}
+
+		if got.Dir != "/site" {
+			t.Errorf("%s command dir is %s, want %s", test.name, got.Dir, "/site")
+		}
+
+		if len(test.npm.CacheDirectory) > 0 && !slices.Contains(got.Env, "npm_config_cache=/cache") {
+			t.Errorf("%s command env is missing npm cache", test.name)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Output represents the plugin configuration for
+// inspecting the output directory of the site.
+type Output struct {
+	// size limits for files matching glob patterns, e.g. **/*.js < 200KB
+	Budgets []string
+	// verify the links to other sites from the rendered pages
+	CheckExternalLinks bool
+	// verify the links on the rendered pages resolve to the output directory
+	CheckLinks bool
+	// patterns for the external links to skip
+	ExternalIgnore []string
+	// patterns for the external links to check, which checks every link when empty
+	ExternalInclude []string
+	// maximum concurrent requests to each host for external links
+	ExternalPerHost int
+	// maximum requests per second for external links
+	ExternalRate int
+	// number of times to retry failed requests for external links
+	ExternalRetries int
+	// timeout for each request for external links
+	ExternalTimeout string
+	// minimum severity of the problems on the rendered pages which fails the step
+	HTMLThreshold string
+	// maximum size of any single file in the output directory
+	FileBudget string
+	// maximum total size of the output directory
+	TotalBudget string
+	// filesystem path or go-getter url for the manifest of a previous build to diff against
+	Baseline string
+	// write a manifest of every file in the output directory
+	Manifest bool
+	// filesystem path to write the manifest to
+	ManifestPath string
+	// write compressed variants of the text files in the output directory
+	Precompress bool
+	// formats to write the compressed variants in
+	PrecompressFormats []string
+	// minimum size of the files to compress
+	PrecompressMinSize string
+	// verify the rendered pages have no problems
+	ValidateHTML bool
+}
+
+// Enabled returns whether the output directory should be inspected.
+func (o *Output) Enabled() bool {
+	return o.Manifest || o.Precompress || o.CheckLinks || o.CheckExternalLinks || o.ValidateHTML || len(o.Baseline) > 0 || o.budgeted()
+}
+
+// budgeted returns whether any size budgets are provided.
+func (o *Output) budgeted() bool {
+	return len(o.TotalBudget) > 0 || len(o.FileBudget) > 0 || len(o.Budgets) > 0
+}
+
+// Validate verifies the Output is properly configured.
+func (o *Output) Validate() error {
+	logrus.Trace("validating output configuration")
+
+	v := new(ValidationError)
+
+	_, err := o.budgets()
+	v.merge(err)
+
+	o.validatePrecompress(v)
+	o.validateExternal(v)
+	o.validateThreshold(v)
+
+	return v.err()
+}
+
+// manifestPath returns the filesystem path the manifest is
+// written to, which defaults to next to the output directory.
+func (o *Output) manifestPath(dir string) string {
+	if len(o.ManifestPath) > 0 {
+		return o.ManifestPath
+	}
+
+	return filepath.Join(dir, _manifestFile)
+}
+
+// Exec inspects the output directory of the site and writes
+// the reports for it to the provided directory.
+func (o *Output) Exec(ctx context.Context, output, dir, baseURL string) error {
+	logrus.Debug("inspecting output directory")
+
+	manifest, err := buildManifest(output)
+	if err != nil {
+		return err
+	}
+
+	// check if the output should be precompressed, which keeps the manifest
+	// captured before compression so the reports and budgets only include
+	// the files rendered by hugo instead of their compressed variants
+	if o.Precompress {
+		err = o.precompress(output, manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the manifest should be written
+	if o.Manifest {
+		err = manifest.Write(o.manifestPath(dir))
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the output should be diffed against a previous build
+	if len(o.Baseline) > 0 {
+		baseline, err := readManifest(ctx, o.Baseline)
+		if err != nil {
+			return err
+		}
+
+		d := diffManifests(baseline, manifest)
+
+		if d.Empty() {
+			logrus.Info("no files changed compared to the baseline manifest")
+		} else {
+			logrus.Infof("%d file(s) added, %d removed and %d changed compared to the baseline manifest",
+				len(d.Added), len(d.Removed), len(d.Changed))
+		}
+
+		// always write the diff so the summary can be posted for every build
+		err = d.Write(dir, baseURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the rendered pages should be validated
+	if o.ValidateHTML {
+		err = o.validateHTML(output, dir, manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the links on the rendered pages should be verified
+	if o.CheckLinks {
+		err = o.checkLinks(output, baseURL, manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the links to other sites should be verified
+	if o.CheckExternalLinks {
+		err = o.checkExternalLinks(ctx, output, dir, baseURL, manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the output should be verified against the size budgets
+	if o.budgeted() {
+		return o.checkBudgets(manifest)
+	}
+
+	return nil
+}
+
+// siteBaseURL returns the base url the site is built with, which falls
+// back to the site configuration when no base url is provided.
+func (p *Plugin) siteBaseURL() string {
+	// check if a base url is provided
+	if len(p.Build.BaseURL) > 0 {
+		return p.Build.BaseURL
+	}
+
+	site, err := p.Config.Site()
+	if err != nil {
+		logrus.Debugf("unable to read base url from site configuration: %v", err)
+
+		return ""
+	}
+
+	return site.BaseURL
+}
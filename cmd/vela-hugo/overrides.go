@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// pattern for the temporary config file the overrides are written to.
+	_overridesPattern = "vela-hugo-overrides-*.yaml"
+	// source reported for site configuration read from the overrides.
+	_overridesSource = "config_overrides"
+)
+
+// writeOverrides renders the site configuration overrides into a temporary
+// config file which is layered on top of the site configuration by Hugo.
+//
+// The returned function removes the temporary config file.
+func (c *Config) writeOverrides() (func(), error) {
+	logrus.Trace("writing site configuration overrides")
+
+	// YAML keeps whole numbers from the JSON overrides as integers
+	data, err := yaml.Marshal(c.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render config overrides: %w", err)
+	}
+
+	file, err := afero.TempFile(appFS, "", _overridesPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.overlay = file.Name()
+
+	logrus.Debugf("writing config overrides to %s", c.overlay)
+
+	return func() {
+		err := appFS.Remove(c.overlay)
+		if err != nil {
+			logrus.Warnf("unable to remove config overrides %s: %v", c.overlay, err)
+		}
+
+		c.overlay = ""
+	}, nil
+}
+
+// configFiles returns the comma-separated config files provided to Hugo.
+func (c *Config) configFiles() (string, error) {
+	// check if an overlay for the overrides was written
+	if len(c.overlay) == 0 {
+		return c.File, nil
+	}
+
+	files := c.File
+
+	// Hugo skips the default config file when config files are
+	// provided, so the default must be layered under the overrides
+	if len(files) == 0 {
+		name, err := c.defaultSiteFile()
+		if err != nil {
+			return "", err
+		}
+
+		files = name
+	}
+
+	if len(files) == 0 {
+		return c.overlay, nil
+	}
+
+	return strings.Join([]string{files, c.overlay}, ","), nil
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestConfig_writeOverrides(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	c := &Config{
+		Overrides: map[string]any{
+			"baseURL":    "https://preview.example.com/",
+			"pagination": map[string]any{"pagerSize": float64(20)},
+		},
+	}
+
+	cleanup, err := c.writeOverrides()
+	if err != nil {
+		t.Fatalf("writeOverrides returned err: %v", err)
+	}
+
+	path := c.overlay
+
+	got, err := a.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read overrides %s: %v", path, err)
+	}
+
+	want := "baseURL: https://preview.example.com/\npagination:\n    pagerSize: 20\n"
+
+	if string(got) != want {
+		t.Errorf("writeOverrides is %q, want %q", got, want)
+	}
+
+	cleanup()
+
+	exists, _ := a.Exists(path)
+	if exists || len(c.overlay) > 0 {
+		t.Errorf("writeOverrides cleanup should have removed %s", path)
+	}
+}
+
+func TestConfig_configFiles(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		config Config
+		files  []string
+		want   string
+	}{
+		{
+			name:   "no overrides",
+			config: Config{File: "hugo.toml,extra.toml"},
+			want:   "hugo.toml,extra.toml",
+		},
+		{
+			name:   "overrides with config files",
+			config: Config{File: "hugo.toml,extra.toml", overlay: "/tmp/overrides.yaml"},
+			want:   "hugo.toml,extra.toml,/tmp/overrides.yaml",
+		},
+		{
+			name:   "overrides with default config file",
+			config: Config{SourceDirectory: "/site", overlay: "/tmp/overrides.yaml"},
+			files:  []string{"/site/config.yaml"},
+			want:   "config.yaml,/tmp/overrides.yaml",
+		},
+		{
+			name:   "overrides without config file",
+			config: Config{SourceDirectory: "/site", overlay: "/tmp/overrides.yaml"},
+			want:   "/tmp/overrides.yaml",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		a := &afero.Afero{Fs: appFS}
+
+		for _, path := range test.files {
+			err := a.WriteFile(path, []byte(""), 0644)
+			if err != nil {
+				t.Errorf("unable to create file %s: %v", path, err)
+			}
+		}
+
+		got, err := test.config.configFiles()
+		if err != nil {
+			t.Errorf("%s configFiles returned err: %v", test.name, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%s configFiles is %s, want %s", test.name, got, test.want)
+		}
+	}
+}
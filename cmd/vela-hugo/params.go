@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// paramKey matches a valid segment of a site param key.
+	paramKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	// secretKey matches environment variables which likely contain a secret.
+	secretKey = regexp.MustCompile(`(?i)(secret|token|passw|key|credential|auth|private)`)
+)
+
+// parseObject parses the JSON object provided to the plugin for the
+// parameter, which is how Vela passes map parameters to the plugin.
+func parseObject(name, value string) (map[string]any, error) {
+	// check if a value is provided
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+
+	object := make(map[string]any)
+
+	err := json.Unmarshal([]byte(value), &object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s as a JSON object: %w", name, err)
+	}
+
+	return object, nil
+}
+
+// validateParams verifies the keys for the site params can be
+// expressed as the environment variables Hugo reads.
+func validateParams(params map[string]any) error {
+	for _, key := range flattenParams(nil, params) {
+		for _, segment := range key.path {
+			if !paramKey.MatchString(segment) {
+				return fmt.Errorf("invalid param key %s: only letters, numbers and underscores are supported", strings.Join(key.path, "."))
+			}
+		}
+	}
+
+	return nil
+}
+
+// paramsEnv converts the site params into the environment
+// variables Hugo reads to override the site configuration.
+//
+// https://gohugo.io/configuration/introduction/#environment-variables
+func paramsEnv(params map[string]any) []string {
+	var env []string
+
+	for _, key := range flattenParams(nil, params) {
+		// Hugo uses the character after HUGO as the delimiter, which
+		// allows keys with underscores by delimiting with an "x"
+		delimiter := "_"
+
+		segments := []string{"HUGO", "PARAMS"}
+
+		for _, segment := range key.path {
+			if strings.Contains(segment, "_") {
+				delimiter = "x"
+			}
+
+			// uppercase the segments to keep the delimiter distinct
+			segments = append(segments, strings.ToUpper(segment))
+		}
+
+		name := strings.Join(segments, delimiter)
+
+		env = append(env, fmt.Sprintf("%s=%s", name, key.value))
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// param represents a flattened site param.
+type param struct {
+	path  []string
+	value string
+}
+
+// flattenParams flattens the nested site params into their key paths and values.
+func flattenParams(prefix []string, params map[string]any) []param {
+	var flattened []param
+
+	for _, key := range sortedKeys(params) {
+		path := append(append([]string{}, prefix...), key)
+
+		switch value := params[key].(type) {
+		case map[string]any:
+			flattened = append(flattened, flattenParams(path, value)...)
+		case string:
+			flattened = append(flattened, param{path: path, value: value})
+		case nil:
+			flattened = append(flattened, param{path: path})
+		case bool:
+			flattened = append(flattened, param{path: path, value: strconv.FormatBool(value)})
+		case float64:
+			// format without an exponent so large integers stay integers
+			flattened = append(flattened, param{path: path, value: strconv.FormatFloat(value, 'f', -1, 64)})
+		default:
+			// encoding a decoded JSON value never fails
+			data, _ := json.Marshal(value)
+
+			flattened = append(flattened, param{path: path, value: string(data)})
+		}
+	}
+
+	return flattened
+}
+
+// maskEnv masks the value of the environment variable if it likely contains a secret.
+func maskEnv(env string) string {
+	name, _, _ := strings.Cut(env, "=")
+
+	if secretKey.MatchString(name) {
+		return name + "=***"
+	}
+
+	return env
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseObject(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		value   string
+		want    map[string]any
+	}{
+		{
+			failure: false,
+			name:    "no params provided",
+			value:   "",
+			want:    nil,
+		},
+		{
+			failure: false,
+			name:    "params provided",
+			value:   `{"env": "staging", "social": {"github": "go-vela"}}`,
+			want: map[string]any{
+				"env":    "staging",
+				"social": map[string]any{"github": "go-vela"},
+			},
+		},
+		{
+			failure: true,
+			name:    "params not a JSON object",
+			value:   "env=staging",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseObject("params", test.value)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s parseObject should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s parseObject returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s parseObject is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func Test_validateParams(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		params  map[string]any
+	}{
+		{
+			failure: false,
+			name:    "valid keys",
+			params:  map[string]any{"api_key": "foo", "social": map[string]any{"github": "go-vela"}},
+		},
+		{
+			failure: true,
+			name:    "key with dash",
+			params:  map[string]any{"api-key": "foo"},
+		},
+		{
+			failure: true,
+			name:    "nested key with space",
+			params:  map[string]any{"social": map[string]any{"git hub": "go-vela"}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := validateParams(test.params)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s validateParams should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s validateParams returned err: %v", test.name, err)
+		}
+	}
+}
+
+func Test_paramsEnv(t *testing.T) {
+	params := map[string]any{
+		"env":      "staging",
+		"debug":    true,
+		"weight":   float64(10),
+		"count":    float64(1000000),
+		"ratio":    0.25,
+		"authors":  []any{"a", "b"},
+		"api_key":  "secret",
+		"social":   map[string]any{"github": "go-vela"},
+		"disabled": nil,
+	}
+
+	want := []string{
+		"HUGO_PARAMS_AUTHORS=[\"a\",\"b\"]",
+		"HUGO_PARAMS_COUNT=1000000",
+		"HUGO_PARAMS_DEBUG=true",
+		"HUGO_PARAMS_DISABLED=",
+		"HUGO_PARAMS_ENV=staging",
+		"HUGO_PARAMS_RATIO=0.25",
+		"HUGO_PARAMS_SOCIAL_GITHUB=go-vela",
+		"HUGO_PARAMS_WEIGHT=10",
+		"HUGOxPARAMSxAPI_KEY=secret",
+	}
+
+	got := paramsEnv(params)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paramsEnv is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Plan returns the sequence of steps Exec runs for the plugin, with the
+// commands formatted the same way they are output when they are run.
+func (p *Plugin) Plan(ctx context.Context) ([]string, error) {
+	logrus.Debug("planning plugin with provided configuration")
+
+	var steps []string
+
+	// command adds the step for running the command
+	command := func(e *exec.Cmd) {
+		step := "$ " + trace(e)
+
+		if len(e.Dir) > 0 {
+			step = fmt.Sprintf("$ cd %s && %s", e.Dir, trace(e))
+		}
+
+		steps = append(steps, step)
+	}
+
+	// write adds the step for writing the file
+	write := func(description, path string) {
+		steps = append(steps, fmt.Sprintf("# write %s to %s", description, path))
+	}
+
+	// output hugo version for troubleshooting
+	command(versionCmd(ctx))
+
+	// check if the npm packages should be installed
+	if p.NPM.Install {
+		cmd, err := p.NPM.command(ctx, p.Config.SourceDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		command(cmd)
+	}
+
+	// check if the site configuration should be overridden
+	if len(p.Config.Overrides) > 0 {
+		// use a placeholder for the temporary config file written by Exec
+		p.Config.overlay = filepath.Join(os.TempDir(), _overridesPattern)
+
+		defer func() { p.Config.overlay = "" }()
+
+		write("config overrides", p.Config.overlay)
+	}
+
+	// check if the effective configuration should be printed
+	if p.Config.Print {
+		command(p.hugoCmd(ctx, "config", "--format", "json"))
+
+		path := p.Config.PrintFile
+		if len(path) == 0 {
+			path = filepath.Join(p.Config.reportDirectory(), _printConfigFile)
+		}
+
+		write("effective hugo configuration", path)
+	}
+
+	// check if the site should be verified to build reproducibly
+	if p.Build.Reproducible {
+		// use a placeholder for the temporary directories written by Exec
+		for range 2 {
+			command(p.buildCmd(ctx, filepath.Join(os.TempDir(), _reproduciblePattern)))
+		}
+
+		steps = append(steps, "# compare the builds for reproducibility")
+	}
+
+	// run the hugo plugin with the provided flags
+	command(p.Command(ctx))
+
+	// check if the hugo modules should be captured
+	if p.Module.Enabled() || p.SBOM.Enabled {
+		command(p.hugoCmd(ctx, "mod", "graph"))
+		command(p.hugoCmd(ctx, "config", "mounts"))
+	}
+
+	// check if a software bill of materials should be written
+	if p.SBOM.Enabled {
+		name := _sbomCycloneDXFile
+		if p.SBOM.Format == _sbomSPDX {
+			name = _sbomSPDXFile
+		}
+
+		write("software bill of materials", filepath.Join(p.Config.reportDirectory(), name))
+	}
+
+	// check if the hugo modules report should be written
+	if p.Module.Report {
+		write("hugo modules report", filepath.Join(p.Config.reportDirectory(), _moduleReportJSON))
+		write("hugo modules report", filepath.Join(p.Config.reportDirectory(), _moduleReportMarkdown))
+	}
+
+	// check if the output should be precompressed
+	if p.Output.Precompress {
+		steps = append(steps, "# precompress the output with "+strings.Join(p.Output.PrecompressFormats, ", "))
+	}
+
+	// check if the manifest should be written
+	if p.Output.Manifest {
+		write("manifest", p.Output.manifestPath(p.Config.reportDirectory()))
+	}
+
+	// check if the output should be diffed against a previous build
+	if len(p.Output.Baseline) > 0 {
+		steps = append(steps, "# compare the output to the baseline manifest "+p.Output.Baseline)
+
+		write("output diff", filepath.Join(p.Config.reportDirectory(), _diffReportJSON))
+		write("output diff", filepath.Join(p.Config.reportDirectory(), _diffReportMarkdown))
+	}
+
+	// check if the rendered pages should be validated
+	if p.Output.ValidateHTML {
+		steps = append(steps, "# validate the rendered pages")
+
+		write("html validation report", filepath.Join(p.Config.reportDirectory(), _htmlReportFile))
+	}
+
+	// check if the links on the rendered pages should be verified
+	if p.Output.CheckLinks {
+		steps = append(steps, "# check the links on the rendered pages")
+	}
+
+	// check if the links to other sites should be verified
+	if p.Output.CheckExternalLinks {
+		steps = append(steps, "# check the external links on the rendered pages")
+
+		write("external link report", filepath.Join(p.Config.reportDirectory(), _externalReportFile))
+	}
+
+	// check if the output should be verified against the size budgets
+	if p.Output.budgeted() {
+		steps = append(steps, "# check the output against the size budgets")
+	}
+
+	// check if the output directory should be packaged
+	if p.Artifact.Enabled {
+		path := p.Artifact.path(p.Config.outputDirectory())
+
+		write("artifact", path)
+		write("artifact checksum", path+".sha256")
+	}
+
+	return steps, nil
+}
+
+// DryRun outputs the sequence of steps Exec runs for the plugin without running them.
+func (p *Plugin) DryRun(ctx context.Context) error {
+	steps, err := p.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("dry run - the following steps would be executed:")
+
+	for _, step := range steps {
+		fmt.Println(step)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPlugin_Plan(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	for _, path := range []string{"/site/package.json", "/site/package-lock.json"} {
+		_, err := appFS.Create(path)
+		if err != nil {
+			t.Errorf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	p := &Plugin{
+		Artifact: &Artifact{Enabled: true, Format: _artifactZip},
+		Build:    &Build{BaseURL: "https://docs.example.com/"},
+		Config: &Config{
+			File:            "hugo.toml",
+			SourceDirectory: "/site",
+			Overrides:       map[string]any{"title": "Preview"},
+			Print:           true,
+		},
+		Module: &Module{Report: true},
+		NPM:    &NPM{Install: true},
+		Output: &Output{
+			Baseline:           "/baseline.json",
+			CheckExternalLinks: true,
+			CheckLinks:         true,
+			Manifest:           true,
+			Precompress:        true,
+			PrecompressFormats: []string{_precompressGzip, _precompressBrotli},
+			TotalBudget:        "10MB",
+			ValidateHTML:       true,
+		},
+		SBOM:  &SBOM{Enabled: true, Format: _sbomSPDX},
+		Theme: &Theme{},
+	}
+
+	overlay := filepath.Join(os.TempDir(), _overridesPattern)
+	flags := "--baseURL=https://docs.example.com/ --config=hugo.toml," + overlay + " --source=/site"
+
+	want := []string{
+		"$ /bin/hugo version",
+		"$ cd /site && npm ci",
+		"# write config overrides to " + overlay,
+		"$ /bin/hugo config --format json " + flags,
+		"# write effective hugo configuration to /site/hugo-config.json",
+		"$ /bin/hugo " + flags,
+		"$ /bin/hugo mod graph " + flags,
+		"$ /bin/hugo config mounts " + flags,
+		"# write software bill of materials to /site/sbom.spdx.json",
+		"# write hugo modules report to /site/hugo-modules.json",
+		"# write hugo modules report to /site/hugo-modules.md",
+		"# precompress the output with gzip, brotli",
+		"# write manifest to /site/manifest.json",
+		"# compare the output to the baseline manifest /baseline.json",
+		"# write output diff to /site/output-diff.json",
+		"# write output diff to /site/output-diff.md",
+		"# validate the rendered pages",
+		"# write html validation report to /site/html-validation.json",
+		"# check the links on the rendered pages",
+		"# check the external links on the rendered pages",
+		"# write external link report to /site/external-links.json",
+		"# check the output against the size budgets",
+		"# write artifact to /site/public.zip",
+		"# write artifact checksum to /site/public.zip.sha256",
+	}
+
+	got, err := p.Plan(t.Context())
+	if err != nil {
+		t.Fatalf("Plan returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan is %q, want %q", got, want)
+	}
+
+	if len(p.Config.overlay) > 0 {
+		t.Errorf("Plan should not keep the config overrides placeholder")
+	}
+}
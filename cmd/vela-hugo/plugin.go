@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/sirupsen/logrus"
@@ -14,15 +15,46 @@ import (
 var appFS = afero.NewOsFs()
 
 type Plugin struct {
+	// artifact arguments loaded for the plugin
+	Artifact *Artifact
 	// build arguments loaded for the plugin
 	Build *Build
 	// config arguments loaded for the plugin
 	Config *Config
+	// module arguments loaded for the plugin
+	Module *Module
+	// npm arguments loaded for the plugin
+	NPM *NPM
+	// output arguments loaded for the plugin
+	Output *Output
+	// sbom arguments loaded for the plugin
+	SBOM *SBOM
 	// theme arguments loaded for the plugin
 	Theme *Theme
 }
 
+// Command formats the hugo command used to build the site.
 func (p *Plugin) Command(ctx context.Context) *exec.Cmd {
+	// run the hugo plugin with the provided flags
+	return p.hugoCmd(ctx)
+}
+
+// hugoCmd formats a hugo subcommand with the same
+// flags and environment used to build the site.
+func (p *Plugin) hugoCmd(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, _hugo, append(args, p.flags()...)...)
+
+	// check if site params are provided
+	if len(p.Config.Params) > 0 {
+		// override the site params through the environment
+		cmd.Env = append(os.Environ(), paramsEnv(p.Config.Params)...)
+	}
+
+	return cmd
+}
+
+// flags formats the hugo flags from the plugin configuration.
+func (p *Plugin) flags() []string {
 	// variable to store flags for command
 	var flags []string
 
@@ -56,10 +88,16 @@ func (p *Plugin) Command(ctx context.Context) *exec.Cmd {
 		flags = append(flags, fmt.Sprintf("--cacheDir=%s", p.Config.CacheDirectory))
 	}
 
+	// capture the config files including the overrides
+	files, err := p.Config.configFiles()
+	if err != nil {
+		logrus.Warnf("unable to find default site configuration: %v", err)
+	}
+
 	// check if a config file is provided
-	if len(p.Config.File) > 0 {
+	if len(files) > 0 {
 		// add flag for the provided config
-		flags = append(flags, fmt.Sprintf("--config=%s", p.Config.File))
+		flags = append(flags, fmt.Sprintf("--config=%s", files))
 	}
 
 	// check if a config directory is provided
@@ -110,8 +148,7 @@ func (p *Plugin) Command(ctx context.Context) *exec.Cmd {
 		flags = append(flags, fmt.Sprintf("--themesDir=%s", p.Theme.Directory))
 	}
 
-	// run the hugo plugin with the provided flags
-	return exec.CommandContext(ctx, _hugo, flags...)
+	return flags
 }
 
 // Exec formats and runs the commands for the plugin.
@@ -119,34 +156,175 @@ func (p *Plugin) Exec(ctx context.Context) error {
 	logrus.Debug("running plugin with provided configuration")
 
 	// output hugo version for troubleshooting
-	err := execCmd(versionCmd(ctx))
+	out, err := outputCmd(versionCmd(ctx))
 	if err != nil {
 		return err
 	}
 
+	fmt.Print(string(out))
+
+	// check if the npm packages should be installed
+	if p.NPM.Install {
+		err = p.NPM.Exec(ctx, p.Config.SourceDirectory)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the site configuration should be overridden
+	if len(p.Config.Overrides) > 0 {
+		cleanup, err := p.Config.writeOverrides()
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+	}
+
+	// check if the effective configuration should be printed
+	if p.Config.Print {
+		err = p.printConfig(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the site should be verified to build reproducibly
+	if p.Build.Reproducible {
+		err = p.checkReproducible(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	// run the hugo plugin with the provided flags
 	err = execCmd(p.Command(ctx))
 	if err != nil {
 		return err
 	}
 
+	// variable to store the hugo modules used by the site
+	var modules *ModuleReport
+
+	// check if the hugo modules should be captured
+	if p.Module.Enabled() || p.SBOM.Enabled {
+		modules, err = p.modules(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if a software bill of materials should be written
+	if p.SBOM.Enabled {
+		// capture the information for the hugo binary
+		info, err := parseHugoInfo(out)
+		if err != nil {
+			return err
+		}
+
+		site, err := p.site(info, modules)
+		if err != nil {
+			return err
+		}
+
+		err = p.SBOM.Write(site, p.Config.reportDirectory())
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the hugo modules should be audited
+	if p.Module.Enabled() {
+		err = p.Module.Exec(modules, p.Config.reportDirectory())
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the output directory should be inspected
+	if p.Output.Enabled() {
+		err = p.Output.Exec(ctx, p.Config.outputDirectory(), p.Config.reportDirectory(), p.siteBaseURL())
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if the output directory should be packaged
+	if p.Artifact.Enabled {
+		err = p.Artifact.Exec(p.Config.outputDirectory())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (p *Plugin) Validate() error {
 	logrus.Debug("validating plugin configuration")
 
+	// capture every problem with the parameters
+	v := new(ValidationError)
+
+	// validate artifact configuration
+	v.merge(p.Artifact.Validate())
+
+	// validate build configuration
+	v.merge(p.Build.Validate())
+
 	// validate config configuration
-	err := p.Config.Validate()
+	v.merge(p.Config.Validate())
+
+	// validate output configuration
+	v.merge(p.Output.Validate())
+
+	// validate sbom configuration
+	v.merge(p.SBOM.Validate())
+
+	// validate theme configuration
+	v.merge(p.Theme.Validate(p.Config.SourceDirectory))
+
+	// check if the site configuration should be validated
+	if p.Config.ValidateSite {
+		p.validateSite(v)
+	}
+
+	// warn when the base url overrides the site configuration
+	p.compareSite()
+
+	return v.err()
+}
+
+// compareSite warns when the provided base url differs from the
+// site configuration, which is skipped when the site configuration
+// can't be read.
+func (p *Plugin) compareSite() {
+	// check if a base url is provided
+	if len(p.Build.BaseURL) == 0 {
+		return
+	}
+
+	site, err := p.Config.Site()
 	if err != nil {
-		return err
+		logrus.Debugf("unable to read site configuration to compare base url: %v", err)
+
+		return
 	}
 
-	// validate theme configuration
-	err = p.Theme.Validate()
+	p.Build.Compare(site)
+}
+
+// validateSite captures the problems with the site configuration,
+// which are reported in the same table as the parameters.
+func (p *Plugin) validateSite(v *ValidationError) {
+	// read the site configuration the same way hugo does
+	site, err := p.Config.Site()
 	if err != nil {
-		return err
+		v.add("config_file", "", "unable to read site configuration: %v", err)
+
+		return
 	}
 
-	return nil
+	// validate site configuration
+	v.merge(site.Validate(p.Build.BaseURL, p.Config.resolve(p.Theme.Directory)))
 }
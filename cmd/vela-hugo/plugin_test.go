@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"slices"
 	"testing"
 )
 
@@ -71,6 +72,22 @@ func TestPlugin_Command(t *testing.T) {
 	}
 }
 
+func TestPlugin_Command_Params(t *testing.T) {
+	p := Plugin{
+		Build: &Build{},
+		Config: &Config{
+			Params: map[string]any{"env": "staging"},
+		},
+		Theme: &Theme{},
+	}
+
+	got := p.Command(t.Context())
+
+	if !slices.Contains(got.Env, "HUGO_PARAMS_ENV=staging") {
+		t.Errorf("Command env is missing site params: %v", got.Env)
+	}
+}
+
 func TestPlugin_Exec(t *testing.T) {
 	// setup tests
 	tests := []struct {
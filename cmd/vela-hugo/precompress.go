@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// gzip format for the precompressed files.
+	_precompressGzip = "gzip"
+	// brotli format for the precompressed files.
+	_precompressBrotli = "brotli"
+)
+
+// precompressExtensions contains the file extension written for each format.
+var precompressExtensions = map[string]string{
+	_precompressGzip:   ".gz",
+	_precompressBrotli: ".br",
+}
+
+// compressibleTypes contains the media types other than text/* which are compressed.
+var compressibleTypes = []string{
+	"application/atom+xml",
+	"application/javascript",
+	"application/json",
+	"application/ld+json",
+	"application/manifest+json",
+	"application/rss+xml",
+	"application/wasm",
+	"application/xml",
+	"font/otf",
+	"font/ttf",
+	"image/svg+xml",
+	"image/x-icon",
+	"image/vnd.microsoft.icon",
+}
+
+// compressible returns whether the media type benefits from compression.
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+
+	return strings.HasPrefix(mediaType, "text/") || slices.Contains(compressibleTypes, mediaType)
+}
+
+// validatePrecompress captures the problems with the precompression configuration.
+func (o *Output) validatePrecompress(v *ValidationError) {
+	// check if precompression is enabled
+	if !o.Precompress {
+		return
+	}
+
+	if len(o.PrecompressFormats) == 0 {
+		v.add("precompress_formats", fmt.Sprintf("use %s, %s or both", _precompressGzip, _precompressBrotli), "no precompress formats provided")
+	}
+
+	for _, format := range o.PrecompressFormats {
+		if _, ok := precompressExtensions[format]; !ok {
+			v.add("precompress_formats", fmt.Sprintf("use %s or %s", _precompressGzip, _precompressBrotli),
+				"invalid precompress format provided: %s", format)
+		}
+	}
+
+	// check if a minimum size is provided
+	if len(o.PrecompressMinSize) > 0 {
+		_, err := parseSize(o.PrecompressMinSize)
+		if err != nil {
+			v.add("precompress_min_size", "use a size like 1KB", "%v", err)
+		}
+	}
+}
+
+// precompressed represents a compressed variant written for a file.
+type precompressed struct {
+	// size in bytes of the original file
+	original int64
+	// size in bytes of the compressed variant
+	size int64
+}
+
+// precompress writes the compressed variants for the text files in the
+// manifest above the minimum size, using a bounded number of workers.
+func (o *Output) precompress(output string, m *Manifest) error {
+	minSize := int64(0)
+
+	// check if a minimum size is provided
+	if len(o.PrecompressMinSize) > 0 {
+		size, err := parseSize(o.PrecompressMinSize)
+		if err != nil {
+			return err
+		}
+
+		minSize = size
+	}
+
+	logrus.Infof("precompressing output with %s", strings.Join(o.PrecompressFormats, ", "))
+
+	files := make(chan *ManifestFile)
+	results := make(chan *precompressed)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for range runtime.NumCPU() {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for file := range files {
+				for _, format := range o.PrecompressFormats {
+					result, err := compressFile(filepath.Join(output, filepath.FromSlash(file.Path)), format)
+					if err != nil {
+						once.Do(func() { firstErr = err })
+
+						continue
+					}
+
+					if result != nil {
+						results <- result
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, file := range m.Files {
+			if file.Size >= minSize && compressible(file.ContentType) {
+				files <- file
+			}
+		}
+
+		close(files)
+		wg.Wait()
+		close(results)
+	}()
+
+	var count, saved int64
+
+	for result := range results {
+		count++
+		saved += result.original - result.size
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("unable to precompress output: %w", firstErr)
+	}
+
+	logrus.Infof("wrote %d precompressed file(s) saving %s", count, formatSize(saved))
+
+	return nil
+}
+
+// compressFile writes the compressed variant of the file next to it, which
+// is skipped when it isn't smaller than the original file.
+func compressFile(path, format string) (*precompressed, error) {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	data, err := a.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := new(bytes.Buffer)
+
+	var w io.WriteCloser
+
+	switch format {
+	case _precompressBrotli:
+		w = brotli.NewWriterLevel(b, brotli.BestCompression)
+	default:
+		// leave the gzip header empty to avoid embedding a name or timestamp
+		w, err = gzip.NewWriterLevel(b, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// check if compression helps for the file
+	if b.Len() >= len(data) {
+		logrus.Debugf("skipping %s for %s since it isn't smaller", format, path)
+
+		return nil, nil
+	}
+
+	err = a.WriteFile(path+precompressExtensions[format], b.Bytes(), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &precompressed{original: int64(len(data)), size: int64(b.Len())}, nil
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompressible(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "text/html; charset=utf-8", want: true},
+		{contentType: "text/css; charset=utf-8", want: true},
+		{contentType: "application/javascript", want: true},
+		{contentType: "image/svg+xml", want: true},
+		{contentType: "image/png", want: false},
+		{contentType: "application/gzip", want: false},
+		{contentType: "font/woff2", want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := compressible(test.contentType)
+
+		if got != test.want {
+			t.Errorf("compressible for %s is %v, want %v", test.contentType, got, test.want)
+		}
+	}
+}
+
+func TestOutput_Validate_Precompress(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		output Output
+		want   []string
+	}{
+		{
+			output: Output{PrecompressFormats: []string{"zstd"}},
+		},
+		{
+			output: Output{Precompress: true, PrecompressFormats: []string{_precompressGzip, _precompressBrotli}, PrecompressMinSize: "1KB"},
+		},
+		{
+			output: Output{Precompress: true},
+			want:   []string{"precompress_formats"},
+		},
+		{
+			output: Output{Precompress: true, PrecompressFormats: []string{"zstd"}, PrecompressMinSize: "small"},
+			want:   []string{"precompress_formats", "precompress_min_size"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		var got []string
+
+		err := test.output.Validate()
+		if err != nil {
+			for _, problem := range err.(*ValidationError).Problems {
+				got = append(got, problem.Parameter)
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Validate problems are %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestOutput_Exec_Precompress(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	page := strings.Repeat("<p>compressible content</p>\n", 100)
+
+	for path, content := range map[string]string{
+		"/site/public/docs/index.html": page,
+		"/site/public/images/logo.png": strings.Repeat("png", 1000),
+		"/site/public/js/random.js":    "var a=1;",
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	// setup types
+	o := &Output{
+		Manifest:           true,
+		Precompress:        true,
+		PrecompressFormats: []string{_precompressGzip, _precompressBrotli},
+		PrecompressMinSize: "1KB",
+	}
+
+	err := o.Exec(t.Context(), "/site/public", "/site", "")
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	// verify the compressed variants decompress to the page
+	readers := map[string]func(io.Reader) (io.Reader, error){
+		".gz": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		".br": func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+
+	for ext, reader := range readers {
+		data, err := a.ReadFile("/site/public/docs/index.html" + ext)
+		if err != nil {
+			t.Errorf("unable to read compressed page %s: %v", ext, err)
+
+			continue
+		}
+
+		r, err := reader(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("unable to decompress page %s: %v", ext, err)
+
+			continue
+		}
+
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("unable to decompress page %s: %v", ext, err)
+		}
+
+		if string(got) != page {
+			t.Errorf("decompressed page %s does not match the page", ext)
+		}
+	}
+
+	// verify files below the minimum size or with binary content are skipped
+	for _, path := range []string{
+		"/site/public/index.html.gz",
+		"/site/public/js/random.js.br",
+		"/site/public/images/logo.png.gz",
+	} {
+		_, err := a.Stat(path)
+		if err == nil {
+			t.Errorf("%s should not have been written", path)
+		}
+	}
+
+	// verify the manifest only captures the files rendered by hugo
+	data, err := a.ReadFile("/site/manifest.json")
+	if err != nil {
+		t.Fatalf("unable to read manifest: %v", err)
+	}
+
+	if strings.Contains(string(data), ".html.br") || strings.Contains(string(data), ".html.gz") {
+		t.Errorf("manifest should not contain the compressed variants")
+	}
+}
+
+func TestOutput_Exec_PrecompressBudgets(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	page := strings.Repeat("<p>compressible content</p>\n", 100)
+
+	err := a.WriteFile("/site/public/docs/index.html", []byte(page), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	m, err := buildManifest("/site/public")
+	if err != nil {
+		t.Fatalf("buildManifest returned err: %v", err)
+	}
+
+	// setup types
+	o := &Output{
+		Budgets:            []string{"**/*.html* < " + strconv.FormatInt(int64(len(page)), 10) + "B"},
+		Precompress:        true,
+		PrecompressFormats: []string{_precompressGzip, _precompressBrotli},
+		TotalBudget:        strconv.FormatInt(m.Size, 10) + "B",
+	}
+
+	// the budgets are exactly the size of the files rendered by hugo
+	err = o.Exec(t.Context(), "/site/public", "/site", "")
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	_, err = a.Stat("/site/public/docs/index.html.gz")
+	if err != nil {
+		t.Errorf("compressed variant should have been written: %v", err)
+	}
+}
+
+func TestCompressFile_NotSmaller(t *testing.T) {
+	// setup filesystem
+	a := setupSite(t)
+
+	// compressing already compressed data doesn't help
+	b := new(bytes.Buffer)
+
+	w := gzip.NewWriter(b)
+	_, _ = w.Write([]byte(strings.Repeat("<p>compressible content</p>\n", 100)))
+	_ = w.Close()
+
+	err := a.WriteFile("/site/public/data.txt", b.Bytes(), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	got, err := compressFile("/site/public/data.txt", _precompressGzip)
+	if err != nil {
+		t.Fatalf("compressFile returned err: %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("compressFile is %+v, want nil", got)
+	}
+
+	_, err = a.Stat("/site/public/data.txt.gz")
+	if err == nil {
+		t.Errorf("compressed variant should not have been written")
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// name of the file the effective configuration is written to.
+const _printConfigFile = "hugo-config.json"
+
+// printConfig writes the effective configuration Hugo
+// builds the site with and compares it to the baseline.
+func (p *Plugin) printConfig(ctx context.Context) error {
+	logrus.Debug("printing effective hugo configuration")
+
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// capture the merged configuration with the same flags as the build
+	out, err := outputCmd(p.hugoCmd(ctx, "config", "--format", "json"))
+	if err != nil {
+		return err
+	}
+
+	current := new(bytes.Buffer)
+
+	err = json.Indent(current, out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to parse hugo configuration: %w", err)
+	}
+
+	current.WriteByte('\n')
+
+	fmt.Print(current.String())
+
+	path := p.Config.PrintFile
+	if len(path) == 0 {
+		path = filepath.Join(p.Config.reportDirectory(), _printConfigFile)
+	}
+
+	logrus.Infof("writing effective hugo configuration to %s", path)
+
+	err = a.WriteFile(path, current.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+
+	// check if a baseline is provided
+	if len(p.Config.Baseline) == 0 {
+		return nil
+	}
+
+	baseline, err := a.ReadFile(p.Config.Baseline)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no config baseline found @ %s", p.Config.Baseline)
+		}
+
+		return err
+	}
+
+	changes, err := diffJSON(baseline, current.Bytes())
+	if err != nil {
+		return err
+	}
+
+	// check if the configuration matches the baseline
+	if len(changes) == 0 {
+		logrus.Infof("effective hugo configuration matches baseline %s", p.Config.Baseline)
+
+		return nil
+	}
+
+	logrus.Warnf("effective hugo configuration differs from baseline %s in %d key(s):", p.Config.Baseline, len(changes))
+
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+
+	return nil
+}
+
+// diffJSON compares two JSON documents and returns the
+// keys that were added (+), removed (-) or changed (~).
+func diffJSON(baseline, current []byte) ([]string, error) {
+	before := make(map[string]string)
+	after := make(map[string]string)
+
+	for _, doc := range []struct {
+		data   []byte
+		values map[string]string
+	}{{baseline, before}, {current, after}} {
+		var value any
+
+		err := json.Unmarshal(doc.data, &value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse configuration: %w", err)
+		}
+
+		flattenJSON("", value, doc.values)
+	}
+
+	// capture every key from both documents
+	keys := make(map[string]bool)
+
+	for key := range before {
+		keys[key] = true
+	}
+
+	for key := range after {
+		keys[key] = true
+	}
+
+	var changes []string
+
+	for _, key := range sortedKeys(keys) {
+		old, inBefore := before[key]
+		value, inAfter := after[key]
+
+		switch {
+		case !inBefore:
+			changes = append(changes, fmt.Sprintf("+ %s = %s", key, value))
+		case !inAfter:
+			changes = append(changes, fmt.Sprintf("- %s = %s", key, old))
+		case old != value:
+			changes = append(changes, fmt.Sprintf("~ %s: %s => %s", key, old, value))
+		}
+	}
+
+	return changes, nil
+}
+
+// flattenJSON flattens the nested value into dotted keys with JSON encoded leaf values.
+func flattenJSON(prefix string, value any, values map[string]string) {
+	if m, ok := value.(map[string]any); ok && len(m) > 0 {
+		for key, v := range m {
+			path := key
+			if len(prefix) > 0 {
+				path = prefix + "." + key
+			}
+
+			flattenJSON(path, v, values)
+		}
+
+		return
+	}
+
+	// encoding a decoded JSON value never fails
+	data, _ := json.Marshal(value)
+
+	values[prefix] = string(data)
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_diffJSON(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure  bool
+		name     string
+		baseline string
+		current  string
+		want     []string
+	}{
+		{
+			failure:  false,
+			name:     "identical configuration",
+			baseline: `{"baseurl": "https://example.com/", "params": {"a": 1}}`,
+			current:  `{"params": {"a": 1}, "baseurl": "https://example.com/"}`,
+			want:     nil,
+		},
+		{
+			failure:  false,
+			name:     "changed configuration",
+			baseline: `{"baseurl": "https://example.com/", "params": {"a": 1, "b": [1, 2]}}`,
+			current:  `{"baseurl": "https://staging.example.com/", "params": {"b": [2], "c": true}}`,
+			want: []string{
+				`~ baseurl: "https://example.com/" => "https://staging.example.com/"`,
+				`- params.a = 1`,
+				`~ params.b: [1,2] => [2]`,
+				`+ params.c = true`,
+			},
+		},
+		{
+			failure:  true,
+			name:     "invalid baseline",
+			baseline: `baseurl = "https://example.com/"`,
+			current:  `{}`,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := diffJSON([]byte(test.baseline), []byte(test.current))
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s diffJSON should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s diffJSON returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s diffJSON is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
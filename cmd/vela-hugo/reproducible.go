@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// pattern for the temporary directories the reproducible builds are written to.
+const _reproduciblePattern = "vela-hugo-build-*"
+
+// buildCmd formats the hugo command used to build
+// the site with the output written to the directory.
+func (p *Plugin) buildCmd(ctx context.Context, dir string) *exec.Cmd {
+	// copy the configuration so the same flags are used for the build
+	config := *p.Config
+	config.OutputDirectory = dir
+
+	plugin := *p
+	plugin.Config = &config
+
+	return plugin.Command(ctx)
+}
+
+// checkReproducible builds the site twice into separate directories
+// and verifies both builds produce the same bytes for every file.
+func (p *Plugin) checkReproducible(ctx context.Context) error {
+	logrus.Info("verifying the site builds reproducibly")
+
+	var dirs []string
+
+	for range 2 {
+		dir, err := afero.TempDir(appFS, "", _reproduciblePattern)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err := appFS.RemoveAll(dir)
+			if err != nil {
+				logrus.Warnf("unable to remove build %s: %v", dir, err)
+			}
+		}()
+
+		err = execCmd(p.buildCmd(ctx, dir))
+		if err != nil {
+			return err
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	diffs, err := diffDirectories(dirs[0], dirs[1])
+	if err != nil {
+		return err
+	}
+
+	// check if the builds are identical
+	if len(diffs) == 0 {
+		logrus.Info("site builds reproducibly")
+
+		return nil
+	}
+
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+
+	return fmt.Errorf("site does not build reproducibly: %d file(s) differ between builds", len(diffs))
+}
+
+// diffDirectories compares the files in the directories and returns the
+// files only in the first (-), only in the second (+) or that differ (~).
+func diffDirectories(first, second string) ([]string, error) {
+	firstManifest, err := buildManifest(first)
+	if err != nil {
+		return nil, err
+	}
+
+	secondManifest, err := buildManifest(second)
+	if err != nil {
+		return nil, err
+	}
+
+	d := diffManifests(firstManifest, secondManifest)
+
+	var diffs []string
+
+	for _, file := range d.Removed {
+		diffs = append(diffs, fmt.Sprintf("- %s (only in first build)", file.Path))
+	}
+
+	for _, file := range d.Added {
+		diffs = append(diffs, fmt.Sprintf("+ %s (only in second build)", file.Path))
+	}
+
+	for _, file := range d.Changed {
+		offset, err := firstDifference(filepath.Join(first, file.Path), filepath.Join(second, file.Path))
+		if err != nil {
+			return nil, err
+		}
+
+		diffs = append(diffs, fmt.Sprintf("~ %s (differs at byte %d)", file.Path, offset))
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i][2:] < diffs[j][2:] })
+
+	return diffs, nil
+}
+
+// firstDifference returns the offset of the first byte that differs between the files.
+func firstDifference(first, second string) (int, error) {
+	a, err := afero.ReadFile(appFS, first)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := afero.ReadFile(appFS, second)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i, nil
+		}
+	}
+
+	return min(len(a), len(b)), nil
+}
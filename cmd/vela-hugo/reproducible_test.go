@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPlugin_buildCmd(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build:  &Build{Draft: true},
+		Config: &Config{OutputDirectory: "public", SourceDirectory: "/site"},
+		Theme:  &Theme{},
+	}
+
+	got := p.buildCmd(t.Context(), "/tmp/build")
+
+	want := []string{_hugo, "--buildDrafts", "--destination=/tmp/build", "--source=/site"}
+
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("buildCmd is %v, want %v", got.Args, want)
+	}
+
+	if p.Config.OutputDirectory != "public" {
+		t.Errorf("buildCmd should not change the output directory of the plugin")
+	}
+}
+
+func Test_diffDirectories(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	for path, content := range map[string]string{
+		"/first/index.html":      "<html></html>",
+		"/first/sitemap.xml":     "<lastmod>2025-01-01T00:00:00Z</lastmod>",
+		"/first/old.html":        "old",
+		"/second/index.html":     "<html></html>",
+		"/second/sitemap.xml":    "<lastmod>2025-01-02T00:00:00Z</lastmod>",
+		"/second/posts/new.html": "new",
+	} {
+		err := a.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	want := []string{
+		"- old.html (only in first build)",
+		"+ posts/new.html (only in second build)",
+		"~ sitemap.xml (differs at byte 18)",
+	}
+
+	got, err := diffDirectories("/first", "/second")
+	if err != nil {
+		t.Fatalf("diffDirectories returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffDirectories is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,521 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/vela-hugo/version"
+)
+
+const (
+	// CycloneDX format for the software bill of materials.
+	_sbomCycloneDX = "cyclonedx"
+	// SPDX format for the software bill of materials.
+	_sbomSPDX = "spdx"
+	// name of the CycloneDX software bill of materials.
+	_sbomCycloneDXFile = "sbom.cdx.json"
+	// name of the SPDX software bill of materials.
+	_sbomSPDXFile = "sbom.spdx.json"
+)
+
+// spdxID matches the characters not allowed in an SPDX identifier.
+var spdxID = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// SBOM represents the plugin configuration for the software bill of materials.
+type SBOM struct {
+	// write a software bill of materials for the site
+	Enabled bool
+	// format of the software bill of materials
+	Format string
+}
+
+// component represents software described in the software bill of materials.
+type component struct {
+	Type         string
+	Name         string
+	Version      string
+	License      string
+	PURL         string
+	Properties   [][2]string
+	Dependencies []*component
+}
+
+// Validate verifies the SBOM is properly configured.
+func (s *SBOM) Validate() error {
+	logrus.Trace("validating sbom configuration")
+
+	// check if the sbom is enabled
+	if !s.Enabled {
+		return nil
+	}
+
+	v := new(ValidationError)
+
+	switch s.Format {
+	case _sbomCycloneDX, _sbomSPDX:
+	default:
+		v.add("sbom_format", fmt.Sprintf("use %s or %s", _sbomCycloneDX, _sbomSPDX),
+			"invalid sbom format provided: %s (supported: %s, %s)", s.Format, _sbomCycloneDX, _sbomSPDX)
+	}
+
+	return v.err()
+}
+
+// Write outputs the software bill of materials for the site to the provided directory.
+func (s *SBOM) Write(site *component, dir string) error {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	var (
+		doc  any
+		path string
+	)
+
+	switch s.Format {
+	case _sbomSPDX:
+		doc = spdxDocument(site)
+		path = filepath.Join(dir, _sbomSPDXFile)
+	default:
+		doc = cycloneDXDocument(site)
+		path = filepath.Join(dir, _sbomCycloneDXFile)
+	}
+
+	// serialize the document as pretty JSON
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("writing software bill of materials to %s", path)
+
+	return a.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// site captures the software used to build the site.
+func (p *Plugin) site(info *HugoInfo, modules *ModuleReport) (*component, error) {
+	site := &component{
+		Type: "application",
+		Name: p.siteName(modules),
+	}
+
+	// capture the plugin used to build the site
+	v := version.New()
+
+	site.Dependencies = append(site.Dependencies, &component{
+		Type:    "application",
+		Name:    "vela-hugo",
+		Version: v.Semantic(),
+		License: "Apache-2.0",
+		PURL:    fmt.Sprintf("pkg:golang/github.com/go-vela/vela-hugo@%s", v.Semantic()),
+		Properties: [][2]string{
+			{"vela-hugo:commit", v.Metadata.GitCommit},
+			{"vela-hugo:go", v.Metadata.GoVersion},
+		},
+	})
+
+	// capture the hugo binary used to build the site
+	site.Dependencies = append(site.Dependencies, &component{
+		Type:    "application",
+		Name:    "hugo",
+		Version: info.Version,
+		License: "Apache-2.0",
+		PURL:    fmt.Sprintf("pkg:golang/github.com/gohugoio/hugo@v%s", info.Version),
+		Properties: [][2]string{
+			{"hugo:extended", fmt.Sprintf("%t", info.Extended)},
+			{"hugo:commit", info.Commit},
+			{"hugo:build_date", info.BuildDate},
+			{"hugo:platform", fmt.Sprintf("%s/%s", info.OS, info.Arch)},
+		},
+	})
+
+	// capture the themes used to build the site
+	site.Dependencies = append(site.Dependencies, p.themes()...)
+
+	// capture the hugo modules used to build the site
+	if modules != nil {
+		site.Dependencies = append(site.Dependencies, moduleComponents(modules.Modules)...)
+	}
+
+	// capture the npm packages used to build the site
+	packages, err := readPackages(p.Config.SourceDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range packages {
+		c := &component{
+			Type:    "library",
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			License: pkg.License,
+			PURL:    npmPURL(pkg),
+		}
+
+		if len(pkg.Integrity) > 0 {
+			c.Properties = append(c.Properties, [2]string{"npm:integrity", pkg.Integrity})
+		}
+
+		if pkg.Dev {
+			c.Properties = append(c.Properties, [2]string{"npm:development", "true"})
+		}
+
+		site.Dependencies = append(site.Dependencies, c)
+	}
+
+	return site, nil
+}
+
+// siteName returns the name used for the site in the software bill of materials.
+func (p *Plugin) siteName(modules *ModuleReport) string {
+	// use the hugo module path for the project when available
+	if modules != nil && len(modules.Project) > 0 && modules.Project != "project" {
+		return modules.Project
+	}
+
+	path, err := filepath.Abs(p.Config.SourceDirectory)
+	if err != nil {
+		return "site"
+	}
+
+	return filepath.Base(path)
+}
+
+// themes captures the themes from the theme directory used to build the site.
+func (p *Plugin) themes() []*component {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// check if a theme is provided
+	if len(p.Theme.Name) == 0 {
+		return nil
+	}
+
+	// Hugo resolves a relative theme directory from the source directory
+	dir := p.Config.resolve(p.Theme.Directory)
+
+	var themes []*component
+
+	for _, name := range strings.Split(p.Theme.Name, ",") {
+		name = strings.TrimSpace(name)
+		path := filepath.Join(dir, name)
+
+		theme := &component{
+			Type: "library",
+			Name: name,
+			PURL: fmt.Sprintf("pkg:generic/%s", url.PathEscape(name)),
+		}
+
+		// capture the license from the theme
+		_, theme.License = detectLicense(path)
+
+		// capture the metadata from the theme configuration
+		data, err := a.ReadFile(filepath.Join(path, "theme.toml"))
+		if err == nil {
+			meta := struct {
+				Name       string `toml:"name"`
+				License    string `toml:"license"`
+				Homepage   string `toml:"homepage"`
+				MinVersion any    `toml:"min_version"`
+			}{}
+
+			err = toml.Unmarshal(data, &meta)
+			if err != nil {
+				logrus.Warnf("unable to parse theme configuration for %s: %v", name, err)
+			}
+
+			if theme.License == _noAssertion && len(meta.License) > 0 {
+				theme.License = meta.License
+			}
+
+			if len(meta.Homepage) > 0 {
+				theme.Properties = append(theme.Properties, [2]string{"hugo:theme:homepage", meta.Homepage})
+			}
+
+			if meta.MinVersion != nil {
+				theme.Properties = append(theme.Properties, [2]string{"hugo:theme:min_version", fmt.Sprint(meta.MinVersion)})
+			}
+		} else if !os.IsNotExist(err) {
+			logrus.Warnf("unable to read theme configuration for %s: %v", name, err)
+		}
+
+		themes = append(themes, theme)
+	}
+
+	return themes
+}
+
+// moduleComponents converts the hugo modules to components.
+func moduleComponents(tree []*ModuleNode) []*component {
+	var components []*component
+
+	for _, node := range tree {
+		// skip modules not used for the site
+		if node.Disabled {
+			continue
+		}
+
+		c := &component{
+			Type:         "library",
+			Name:         node.Path,
+			Version:      node.Version,
+			License:      node.License,
+			PURL:         fmt.Sprintf("pkg:golang/%s", node.Path),
+			Dependencies: moduleComponents(node.Dependencies),
+		}
+
+		if len(node.Version) > 0 {
+			c.PURL = fmt.Sprintf("%s@%s", c.PURL, node.Version)
+		}
+
+		if len(node.Replace) > 0 {
+			c.Properties = append(c.Properties, [2]string{"hugo:module:replace", node.Replace})
+		}
+
+		components = append(components, c)
+	}
+
+	return components
+}
+
+// npmPURL returns the package URL for the npm package.
+func npmPURL(pkg *npmPackage) string {
+	purl := fmt.Sprintf("pkg:npm/%s", strings.ReplaceAll(pkg.Name, "@", "%40"))
+
+	// versions from the package manifest are ranges
+	if len(pkg.Resolved) > 0 || len(pkg.Integrity) > 0 {
+		purl = fmt.Sprintf("%s@%s", purl, pkg.Version)
+	}
+
+	return purl
+}
+
+// ref returns the unique reference for the component.
+func (c *component) ref() string {
+	if len(c.PURL) > 0 {
+		return c.PURL
+	}
+
+	return c.Name
+}
+
+// flatten returns the unique components the component depends on.
+func (c *component) flatten() []*component {
+	var components []*component
+
+	seen := make(map[string]bool)
+
+	var walk func([]*component)
+
+	walk = func(deps []*component) {
+		for _, dep := range deps {
+			if seen[dep.ref()] {
+				continue
+			}
+
+			seen[dep.ref()] = true
+
+			components = append(components, dep)
+
+			walk(dep.Dependencies)
+		}
+	}
+
+	walk(c.Dependencies)
+
+	return components
+}
+
+// cycloneDXDocument formats the site as a CycloneDX document.
+//
+// https://cyclonedx.org/docs/1.5/json/
+func cycloneDXDocument(site *component) map[string]any {
+	v := version.New()
+
+	components := []map[string]any{}
+	dependencies := []map[string]any{cycloneDXDependency(site)}
+
+	for _, c := range site.flatten() {
+		components = append(components, cycloneDXComponent(c))
+		dependencies = append(dependencies, cycloneDXDependency(c))
+	}
+
+	return map[string]any{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": fmt.Sprintf("urn:uuid:%s", uuid()),
+		"version":      1,
+		"metadata": map[string]any{
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"tools": map[string]any{
+				"components": []map[string]any{
+					{"type": "application", "name": "vela-hugo", "version": v.Semantic()},
+				},
+			},
+			"component": cycloneDXComponent(site),
+		},
+		"components":   components,
+		"dependencies": dependencies,
+	}
+}
+
+// cycloneDXComponent formats the component for a CycloneDX document.
+func cycloneDXComponent(c *component) map[string]any {
+	result := map[string]any{
+		"type":    c.Type,
+		"bom-ref": c.ref(),
+		"name":    c.Name,
+	}
+
+	if len(c.Version) > 0 {
+		result["version"] = c.Version
+	}
+
+	if len(c.PURL) > 0 {
+		result["purl"] = c.PURL
+	}
+
+	if len(c.License) > 0 && c.License != _noAssertion {
+		result["licenses"] = []map[string]any{{"expression": c.License}}
+	}
+
+	var properties []map[string]string
+
+	for _, property := range c.Properties {
+		if len(property[1]) > 0 {
+			properties = append(properties, map[string]string{"name": property[0], "value": property[1]})
+		}
+	}
+
+	if len(properties) > 0 {
+		result["properties"] = properties
+	}
+
+	return result
+}
+
+// cycloneDXDependency formats the dependencies of the component for a CycloneDX document.
+func cycloneDXDependency(c *component) map[string]any {
+	dependsOn := []string{}
+
+	for _, dep := range c.Dependencies {
+		dependsOn = append(dependsOn, dep.ref())
+	}
+
+	return map[string]any{
+		"ref":       c.ref(),
+		"dependsOn": dependsOn,
+	}
+}
+
+// spdxDocument formats the site as an SPDX document.
+//
+// https://spdx.github.io/spdx-spec/v2.3/
+func spdxDocument(site *component) map[string]any {
+	v := version.New()
+
+	packages := []map[string]any{spdxPackage(site)}
+	relationships := []map[string]any{
+		{
+			"spdxElementId":      "SPDXRef-DOCUMENT",
+			"relationshipType":   "DESCRIBES",
+			"relatedSpdxElement": spdxRef(site),
+		},
+	}
+
+	for _, c := range append([]*component{site}, site.flatten()...) {
+		if c != site {
+			packages = append(packages, spdxPackage(c))
+		}
+
+		for _, dep := range c.Dependencies {
+			relationships = append(relationships, map[string]any{
+				"spdxElementId":      spdxRef(c),
+				"relationshipType":   "DEPENDS_ON",
+				"relatedSpdxElement": spdxRef(dep),
+			})
+		}
+	}
+
+	return map[string]any{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              site.Name,
+		"documentNamespace": fmt.Sprintf("https://github.com/go-vela/vela-hugo/spdx/%s-%s", url.PathEscape(site.Name), uuid()),
+		"creationInfo": map[string]any{
+			"created":  time.Now().UTC().Format(time.RFC3339),
+			"creators": []string{fmt.Sprintf("Tool: vela-hugo-%s", v.Semantic())},
+		},
+		"packages":      packages,
+		"relationships": relationships,
+	}
+}
+
+// spdxPackage formats the component for an SPDX document.
+func spdxPackage(c *component) map[string]any {
+	license := c.License
+	if len(license) == 0 {
+		license = _noAssertion
+	}
+
+	result := map[string]any{
+		"name":             c.Name,
+		"SPDXID":           spdxRef(c),
+		"downloadLocation": _noAssertion,
+		"filesAnalyzed":    false,
+		"licenseConcluded": _noAssertion,
+		"licenseDeclared":  license,
+		"copyrightText":    _noAssertion,
+	}
+
+	if len(c.Version) > 0 {
+		result["versionInfo"] = c.Version
+	}
+
+	if len(c.PURL) > 0 {
+		result["externalRefs"] = []map[string]string{
+			{
+				"referenceCategory": "PACKAGE-MANAGER",
+				"referenceType":     "purl",
+				"referenceLocator":  c.PURL,
+			},
+		}
+	}
+
+	return result
+}
+
+// spdxRef returns the SPDX identifier for the component.
+func spdxRef(c *component) string {
+	return fmt.Sprintf("SPDXRef-%s", strings.Trim(spdxID.ReplaceAllString(c.ref(), "-"), "-"))
+}
+
+// uuid returns a random version 4 UUID.
+func uuid() string {
+	b := make([]byte, 16)
+
+	// crypto/rand never returns an error
+	_, _ = rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
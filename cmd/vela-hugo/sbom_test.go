@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestSBOM_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		sbom    SBOM
+	}{
+		{
+			failure: false,
+			name:    "sbom disabled",
+			sbom:    SBOM{Enabled: false, Format: "foo"},
+		},
+		{
+			failure: false,
+			name:    "cyclonedx format",
+			sbom:    SBOM{Enabled: true, Format: "cyclonedx"},
+		},
+		{
+			failure: false,
+			name:    "spdx format",
+			sbom:    SBOM{Enabled: true, Format: "spdx"},
+		},
+		{
+			failure: true,
+			name:    "invalid format",
+			sbom:    SBOM{Enabled: true, Format: "foo"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.sbom.Validate()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s Validate should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Validate returned err: %v", test.name, err)
+		}
+	}
+}
+
+func TestSBOM_Write(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/site/themes/docsy/LICENSE", []byte("Apache License\nVersion 2.0, January 2004"), 0644)
+	if err != nil {
+		t.Errorf("unable to create license: %v", err)
+	}
+
+	err = a.WriteFile("/site/package-lock.json", []byte(`{"packages": {"node_modules/postcss": {"version": "8.4.31", "integrity": "sha512-abc"}}}`), 0644)
+	if err != nil {
+		t.Errorf("unable to create lockfile: %v", err)
+	}
+
+	p := &Plugin{
+		Config: &Config{SourceDirectory: "/site"},
+		Theme:  &Theme{Name: "docsy", Directory: "themes"},
+	}
+
+	modules, err := parseGraph([]byte(testGraph))
+	if err != nil {
+		t.Errorf("parseGraph returned err: %v", err)
+	}
+
+	site, err := p.site(&HugoInfo{Version: "0.148.2", Extended: true, OS: "linux", Arch: "amd64"}, modules)
+	if err != nil {
+		t.Errorf("site returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		format string
+		file   string
+		key    string
+		want   int
+	}{
+		{
+			format: "cyclonedx",
+			file:   _sbomCycloneDXFile,
+			key:    "components",
+			// plugin, hugo, theme, 3 modules and 1 npm package
+			want: 7,
+		},
+		{
+			format: "spdx",
+			file:   _sbomSPDXFile,
+			key:    "packages",
+			// site, plugin, hugo, theme, 3 modules and 1 npm package
+			want: 8,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		s := &SBOM{Enabled: true, Format: test.format}
+
+		err := s.Write(site, "/site")
+		if err != nil {
+			t.Errorf("%s Write returned err: %v", test.format, err)
+		}
+
+		data, err := a.ReadFile(filepath.Join("/site", test.file))
+		if err != nil {
+			t.Errorf("unable to read %s: %v", test.file, err)
+		}
+
+		doc := make(map[string][]any)
+
+		// ignore errors from fields which are not lists
+		_ = json.Unmarshal(data, &doc)
+
+		if len(doc[test.key]) != test.want {
+			t.Errorf("%s Write produced %d %s, want %d", test.format, len(doc[test.key]), test.key, test.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+// prefix of the environment variables Vela provides the parameters as.
+const _parameterPrefix = "PARAMETER_"
+
+// parameterTypes contains the schema for parameters which are declared as
+// string flags but are provided as another type in the pipeline.
+var parameterTypes = map[string]*Schema{
+	"artifact_format":         {Type: "string", Enum: []string{_artifactTarGz, _artifactZip}},
+	"config_overrides":        {Type: "object"},
+	"draft":                   {Type: "boolean"},
+	"expired":                 {Type: "boolean"},
+	"future":                  {Type: "boolean"},
+	"params":                  {Type: "object"},
+	"sbom_format":             {Type: "string", Enum: []string{_sbomCycloneDX, _sbomSPDX}},
+	"validate_html_threshold": {Type: "string", Enum: []string{_severityError, _severityWarning, _severityNone}},
+}
+
+// Schema represents a JSON Schema for the plugin parameters.
+//
+// https://json-schema.org/draft/2020-12/json-schema-core
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// parameterSchema generates the JSON Schema for the
+// parameters of the plugin from the flag declarations.
+func parameterSchema(flags []cli.Flag) *Schema {
+	additional := false
+
+	s := &Schema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		Title:                "vela-hugo parameters",
+		Description:          "Parameters for the Vela Hugo plugin",
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: &additional,
+	}
+
+	for _, flag := range flags {
+		name, ok := parameterName(flag)
+		if !ok {
+			continue
+		}
+
+		property := &Schema{}
+
+		switch f := flag.(type) {
+		case *cli.BoolFlag:
+			property.Type = "boolean"
+
+			if f.Value {
+				property.Default = f.Value
+			}
+		case *cli.IntFlag:
+			property.Type = "integer"
+
+			if f.Value != 0 {
+				property.Default = f.Value
+			}
+		case *cli.StringSliceFlag:
+			property.Type = "array"
+			property.Items = &Schema{Type: "string"}
+		case *cli.StringFlag:
+			property.Type = "string"
+
+			if len(f.Value) > 0 {
+				property.Default = f.Value
+			}
+		}
+
+		// check if the parameter is provided as another type
+		if override, ok := parameterTypes[name]; ok {
+			property.Type = override.Type
+			property.Enum = override.Enum
+		}
+
+		if f, ok := flag.(cli.DocGenerationFlag); ok {
+			property.Description = f.GetUsage()
+		}
+
+		s.Properties[name] = property
+	}
+
+	return s
+}
+
+// parameterName returns the name of the parameter for the flag
+// in the pipeline, which is derived from its PARAMETER_* variable.
+func parameterName(flag cli.Flag) (string, bool) {
+	f, ok := flag.(cli.DocGenerationFlag)
+	if !ok {
+		return "", false
+	}
+
+	for _, env := range f.GetEnvVars() {
+		if strings.HasPrefix(env, _parameterPrefix) {
+			return strings.ToLower(strings.TrimPrefix(env, _parameterPrefix)), true
+		}
+	}
+
+	return "", false
+}
+
+// validateParameters verifies the parameters provided through the
+// environment match the schema for the plugin, warning about (or
+// failing on, when strict) parameters which aren't recognized.
+func validateParameters(s *Schema, environ []string, strict bool) error {
+	logrus.Trace("validating parameters against schema")
+
+	v := new(ValidationError)
+
+	for _, env := range slices.Sorted(slices.Values(environ)) {
+		key, value, _ := strings.Cut(env, "=")
+
+		// check if the variable is a parameter
+		if !strings.HasPrefix(key, _parameterPrefix) {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(key, _parameterPrefix))
+
+		property, ok := s.Properties[name]
+		if !ok {
+			suggestion := "remove the parameter"
+
+			// suggest the closest parameter for a likely typo
+			if match, ok := closest(name, sortedKeys(s.Properties)); ok {
+				suggestion = fmt.Sprintf("did you mean %s?", match)
+			}
+
+			if strict {
+				v.add(name, suggestion, "unknown parameter %s", name)
+			} else {
+				logrus.Warnf("unknown parameter %s is ignored: %s", name, suggestion)
+			}
+
+			continue
+		}
+
+		// check if the parameter has a value
+		if len(value) == 0 {
+			continue
+		}
+
+		switch property.Type {
+		case "boolean":
+			_, err := strconv.ParseBool(value)
+			if err != nil {
+				v.add(name, fmt.Sprintf("use true or false, e.g. %s: true", name), "invalid boolean %q", value)
+			}
+		case "integer":
+			_, err := strconv.Atoi(value)
+			if err != nil {
+				v.add(name, fmt.Sprintf("use a whole number, e.g. %s: 1", name), "invalid integer %q", value)
+			}
+		case "object":
+			object := make(map[string]any)
+
+			err := json.Unmarshal([]byte(value), &object)
+			if err != nil {
+				v.add(name, fmt.Sprintf("provide %s as a map of keys and values", name), "invalid object %q", value)
+			}
+		}
+
+		if len(property.Enum) > 0 && !slices.Contains(property.Enum, value) {
+			v.add(name, fmt.Sprintf("use one of: %s", strings.Join(property.Enum, ", ")), "invalid value %q", value)
+		}
+	}
+
+	return v.err()
+}
+
+// schema outputs the JSON Schema for the parameters of the plugin.
+func schema(_ context.Context, c *cli.Command) error {
+	data, err := json.MarshalIndent(parameterSchema(c.Root().Flags), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", data)
+
+	return err
+}
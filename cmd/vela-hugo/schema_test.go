@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+)
+
+func Test_parameterSchema(t *testing.T) {
+	// setup types
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "log.level",
+			Usage:   "set log level",
+			Value:   "info",
+			Sources: cli.EnvVars("PARAMETER_LOG_LEVEL", "HUGO_LOG_LEVEL"),
+		},
+		&cli.BoolFlag{
+			Name:    "config.validate_site",
+			Usage:   "read and validate the site configuration before building",
+			Value:   true,
+			Sources: cli.EnvVars("PARAMETER_VALIDATE_SITE"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "module.allowed_licenses",
+			Usage:   "SPDX licenses allowed",
+			Sources: cli.EnvVars("PARAMETER_MODULE_ALLOWED_LICENSES"),
+		},
+		&cli.StringFlag{
+			Name:    "config.params",
+			Usage:   "site params",
+			Sources: cli.EnvVars("PARAMETER_PARAMS"),
+		},
+		&cli.IntFlag{
+			Name:    "output.external_rate",
+			Usage:   "maximum requests per second",
+			Value:   10,
+			Sources: cli.EnvVars("PARAMETER_EXTERNAL_LINKS_RATE"),
+		},
+		&cli.StringFlag{
+			Name:    "internal",
+			Sources: cli.EnvVars("HUGO_INTERNAL"),
+		},
+	}
+
+	want := map[string]*Schema{
+		"log_level":               {Type: "string", Description: "set log level", Default: "info"},
+		"validate_site":           {Type: "boolean", Description: "read and validate the site configuration before building", Default: true},
+		"module_allowed_licenses": {Type: "array", Description: "SPDX licenses allowed", Items: &Schema{Type: "string"}},
+		"params":                  {Type: "object", Description: "site params"},
+		"external_links_rate":     {Type: "integer", Description: "maximum requests per second", Default: 10},
+	}
+
+	got := parameterSchema(flags)
+
+	if !reflect.DeepEqual(got.Properties, want) {
+		t.Errorf("parameterSchema properties are %+v, want %+v", got.Properties, want)
+	}
+
+	if got.AdditionalProperties == nil || *got.AdditionalProperties {
+		t.Errorf("parameterSchema should not allow additional properties")
+	}
+}
+
+func Test_validateParameters(t *testing.T) {
+	// setup types
+	s := &Schema{
+		Properties: map[string]*Schema{
+			"draft":               {Type: "boolean"},
+			"external_links_rate": {Type: "integer"},
+			"params":              {Type: "object"},
+			"sbom_format":         {Type: "string", Enum: []string{_sbomCycloneDX, _sbomSPDX}},
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		environ []string
+		strict  bool
+		want    []string
+	}{
+		{
+			name:    "valid parameters",
+			environ: []string{"PARAMETER_DRAFT=true", "PARAMETER_EXTERNAL_LINKS_RATE=5", `PARAMETER_PARAMS={"env":"staging"}`, "PARAMETER_SBOM_FORMAT=spdx", "HOME=/root"},
+		},
+		{
+			name:    "invalid parameters",
+			environ: []string{"PARAMETER_DRAFT=yes", "PARAMETER_EXTERNAL_LINKS_RATE=fast", "PARAMETER_PARAMS=env=staging", "PARAMETER_SBOM_FORMAT=swid", "PARAMETER_UNKNOWN=1"},
+			want:    []string{"draft", "external_links_rate", "params", "sbom_format"},
+		},
+		{
+			name:    "unknown parameters in strict mode",
+			environ: []string{"PARAMETER_DRAFT=true", "PARAMETER_DRAFTS=true", "PARAMETER_UNKNOWN=1"},
+			strict:  true,
+			want:    []string{"drafts", "unknown"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := validateParameters(s, test.environ, test.strict)
+
+		var got []string
+
+		var v *ValidationError
+		if errors.As(err, &v) {
+			for _, problem := range v.Problems {
+				got = append(got, problem.Parameter)
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s validateParameters problems are %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func Test_validateParameters_Suggestion(t *testing.T) {
+	// setup types
+	s := &Schema{
+		Properties: map[string]*Schema{
+			"theme_directory": {Type: "string"},
+			"theme_name":      {Type: "string"},
+		},
+	}
+
+	err := validateParameters(s, []string{"PARAMETER_THEME_NMAE=docsy"}, true)
+
+	var v *ValidationError
+	if !errors.As(err, &v) || len(v.Problems) != 1 {
+		t.Fatalf("validateParameters returned %v, want 1 problem", err)
+	}
+
+	if got, want := v.Problems[0].Suggestion, "did you mean theme_name?"; got != want {
+		t.Errorf("validateParameters suggestion is %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,643 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// environment Hugo builds the site for by default.
+	_productionEnvironment = "production"
+	// directory in the config directory loaded for every environment.
+	_defaultEnvironment = "_default"
+)
+
+var (
+	// siteConfigNames contains the names Hugo looks for the root site configuration.
+	siteConfigNames = []string{"hugo", "config"}
+	// siteConfigFormats contains the formats Hugo supports for the site configuration.
+	siteConfigFormats = []string{".toml", ".yaml", ".yml", ".json"}
+)
+
+// SiteConfig represents the Hugo configuration for the site.
+type SiteConfig struct {
+	// hostname (and path) to the root of the site
+	BaseURL string
+	// themes used to build the site
+	Themes []string
+	// language code of the site
+	LanguageCode string
+	// default language of the content for the site
+	DefaultContentLanguage string
+	// languages configured for the site
+	Languages map[string]*SiteLanguage
+	// menus configured for the site
+	Menus map[string][]*MenuEntry
+	// hugo modules imported by the site
+	Imports []string
+	// hugo version required by the site
+	HugoVersion *SiteHugoVersion
+	// files the configuration was read from
+	Files []string
+
+	// merged configuration from all files
+	raw map[string]any
+	// file each configuration key was read from
+	sources map[string]string
+	// problems found decoding the configuration
+	problems []string
+}
+
+// SiteLanguage represents the Hugo configuration for a language of the site.
+type SiteLanguage struct {
+	// hostname (and path) to the root of the language
+	BaseURL string
+	// language code of the language
+	LanguageCode string
+	// menus configured for the language
+	Menus map[string][]*MenuEntry
+}
+
+// SiteHugoVersion represents the Hugo version required by the site.
+type SiteHugoVersion struct {
+	// minimum hugo version required
+	Min string
+	// maximum hugo version supported
+	Max string
+	// whether the extended binary is required
+	Extended bool
+}
+
+// MenuEntry represents an entry in a menu of the site.
+type MenuEntry struct {
+	Identifier string
+	Name       string
+	URL        string
+	PageRef    string
+	Parent     string
+	Weight     int
+}
+
+// Site reads the Hugo configuration for the site the same way Hugo does,
+// merging the root configuration with the config directory for the environment.
+func (c *Config) Site() (*SiteConfig, error) {
+	logrus.Trace("reading site configuration")
+
+	site := &SiteConfig{
+		raw:     make(map[string]any),
+		sources: make(map[string]string),
+	}
+
+	// capture the root configuration files for the site
+	files, err := c.siteFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		err = site.merge(file, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// layer the overrides on top of the root configuration files
+	if len(c.Overrides) > 0 {
+		logrus.Debugf("merging site configuration from %s", _overridesSource)
+
+		mergeValues(site.raw, c.Overrides, _overridesSource, "", site.sources)
+	}
+
+	environment := c.Environment
+	if len(environment) == 0 {
+		environment = _productionEnvironment
+	}
+
+	// Hugo resolves the config directory from the source directory
+	dir := c.resolve(c.Directory)
+
+	// merge the config directory for the default and targeted environment
+	for _, env := range []string{_defaultEnvironment, environment} {
+		err = site.mergeDirectory(filepath.Join(dir, env))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// check if any configuration was found for the site
+	if len(site.Files) == 0 {
+		return nil, fmt.Errorf("no site configuration found @ %s", c.resolve("hugo.toml"))
+	}
+
+	site.decode()
+
+	return site, nil
+}
+
+// siteFiles returns the root configuration files for the site.
+func (c *Config) siteFiles() ([]string, error) {
+	// check if config files are provided
+	if len(c.File) > 0 {
+		var files []string
+
+		for _, file := range strings.Split(c.File, ",") {
+			files = append(files, c.resolve(strings.TrimSpace(file)))
+		}
+
+		return files, nil
+	}
+
+	name, err := c.defaultSiteFile()
+	if err != nil {
+		return nil, err
+	}
+
+	// check if a default config file was found
+	if len(name) == 0 {
+		return nil, nil
+	}
+
+	return []string{c.resolve(name)}, nil
+}
+
+// defaultSiteFile returns the name of the first default
+// config file found in the source directory.
+func (c *Config) defaultSiteFile() (string, error) {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	for _, name := range siteConfigNames {
+		for _, format := range siteConfigFormats {
+			exists, err := a.Exists(c.resolve(name + format))
+			if err != nil {
+				return "", err
+			}
+
+			if exists {
+				return name + format, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// mergeDirectory merges every config file in the directory into the site configuration.
+func (s *SiteConfig) mergeDirectory(dir string) error {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	infos, err := a.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, info := range infos {
+		ext := filepath.Ext(info.Name())
+
+		// skip directories and files in unsupported formats
+		if info.IsDir() || !containsFold(siteConfigFormats, ext) {
+			continue
+		}
+
+		// config files are named after the key they configure, e.g.
+		//
+		// params.toml configures params and menus.en.toml configures languages.en.menus
+		key := strings.ToLower(strings.TrimSuffix(info.Name(), ext))
+
+		if name, lang, ok := strings.Cut(key, "."); ok {
+			key = fmt.Sprintf("languages.%s.%s", lang, name)
+		}
+
+		if containsFold(siteConfigNames, key) {
+			key = ""
+		}
+
+		err = s.merge(filepath.Join(dir, info.Name()), key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// merge reads the config file and merges it into the site configuration at the key.
+func (s *SiteConfig) merge(path, key string) error {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	data, err := a.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no config found @ %s", path)
+		}
+
+		return err
+	}
+
+	values := make(map[string]any)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".json":
+		err = json.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("unsupported format for config %s (supported: json, toml, yaml)", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to parse config %s: %w", path, err)
+	}
+
+	logrus.Debugf("merging site configuration from %s", path)
+
+	s.Files = append(s.Files, path)
+
+	// nest the values under the key for the file
+	dst := s.raw
+
+	if len(key) > 0 {
+		for _, part := range strings.Split(key, ".") {
+			next, ok := dst[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				dst[part] = next
+			}
+
+			dst = next
+		}
+
+		s.sources[key] = path
+	}
+
+	mergeValues(dst, values, path, key, s.sources)
+
+	return nil
+}
+
+// mergeValues deep merges the src into the dst with case-insensitive keys,
+// recording the file each key was read from in the sources.
+func mergeValues(dst, src map[string]any, path, prefix string, sources map[string]string) {
+	for k, v := range src {
+		k = strings.ToLower(k)
+
+		key := k
+		if len(prefix) > 0 {
+			key = prefix + "." + k
+		}
+
+		sources[key] = path
+
+		// check if both values are maps which should be merged
+		srcMap, srcOK := v.(map[string]any)
+		dstMap, dstOK := dst[k].(map[string]any)
+
+		if srcOK {
+			if !dstOK {
+				dstMap = make(map[string]any)
+				dst[k] = dstMap
+			}
+
+			mergeValues(dstMap, srcMap, path, key, sources)
+
+			continue
+		}
+
+		dst[k] = v
+	}
+}
+
+// decode converts the merged configuration into the typed model.
+func (s *SiteConfig) decode() {
+	s.BaseURL = stringValue(s.raw["baseurl"])
+	s.LanguageCode = stringValue(s.raw["languagecode"])
+	s.DefaultContentLanguage = stringValue(s.raw["defaultcontentlanguage"])
+	s.Themes = stringsValue(s.raw["theme"])
+	s.Menus = s.decodeMenus(s.raw, "")
+
+	// capture the languages for the site
+	if languages, ok := s.raw["languages"].(map[string]any); ok {
+		s.Languages = make(map[string]*SiteLanguage)
+
+		for _, lang := range sortedKeys(languages) {
+			values, ok := languages[lang].(map[string]any)
+			if !ok {
+				s.problem("languages."+lang, "expected a table of language settings")
+
+				continue
+			}
+
+			s.Languages[lang] = &SiteLanguage{
+				BaseURL:      stringValue(values["baseurl"]),
+				LanguageCode: stringValue(values["languagecode"]),
+				Menus:        s.decodeMenus(values, "languages."+lang+"."),
+			}
+		}
+	}
+
+	// capture the module settings for the site
+	if module, ok := s.raw["module"].(map[string]any); ok {
+		imports, _ := module["imports"].([]any)
+
+		for i, value := range imports {
+			switch value := value.(type) {
+			case string:
+				s.Imports = append(s.Imports, value)
+			case map[string]any:
+				s.Imports = append(s.Imports, stringValue(value["path"]))
+			default:
+				s.problem(fmt.Sprintf("module.imports[%d]", i), "expected a table with a path")
+			}
+		}
+
+		if version, ok := module["hugoversion"].(map[string]any); ok {
+			extended, _ := version["extended"].(bool)
+
+			s.HugoVersion = &SiteHugoVersion{
+				Min:      stringValue(version["min"]),
+				Max:      stringValue(version["max"]),
+				Extended: extended,
+			}
+		}
+	}
+}
+
+// decodeMenus converts the menus from the values into menu entries.
+func (s *SiteConfig) decodeMenus(values map[string]any, prefix string) map[string][]*MenuEntry {
+	// Hugo supports both the menu and menus keys
+	for _, name := range []string{"menus", "menu"} {
+		raw, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		key := prefix + name
+
+		menus, ok := raw.(map[string]any)
+		if !ok {
+			s.problem(key, "expected a table of menus")
+
+			return nil
+		}
+
+		result := make(map[string][]*MenuEntry)
+
+		for _, menu := range sortedKeys(menus) {
+			entries, ok := menus[menu].([]any)
+			if !ok {
+				s.problem(fmt.Sprintf("%s.%s", key, menu), "expected a list of menu entries")
+
+				continue
+			}
+
+			for i, raw := range entries {
+				entryKey := fmt.Sprintf("%s.%s[%d]", key, menu, i)
+
+				entry, ok := raw.(map[string]any)
+				if !ok {
+					s.problem(entryKey, "expected a table for the menu entry")
+
+					continue
+				}
+
+				weight, ok := intValue(entry["weight"])
+				if !ok {
+					s.problem(entryKey+".weight", fmt.Sprintf("expected an integer but got %v", entry["weight"]))
+				}
+
+				result[menu] = append(result[menu], &MenuEntry{
+					Identifier: stringValue(entry["identifier"]),
+					Name:       stringValue(entry["name"]),
+					URL:        stringValue(entry["url"]),
+					PageRef:    stringValue(entry["pageref"]),
+					Parent:     stringValue(entry["parent"]),
+					Weight:     weight,
+				})
+			}
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// Validate verifies the SiteConfig doesn't contain common mistakes.
+func (s *SiteConfig) Validate(baseURL, themeDirectory string) error {
+	logrus.Trace("validating site configuration")
+
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// check if a base url is provided
+	if len(s.BaseURL) == 0 && len(baseURL) == 0 {
+		s.problem("baseURL", "no baseURL provided in the site configuration or the base_url parameter")
+	}
+
+	// check if the themes exist in the theme directory
+	for _, theme := range s.Themes {
+		// themes with a path are imported as hugo modules
+		if strings.Contains(theme, "/") {
+			continue
+		}
+
+		exists, err := a.DirExists(filepath.Join(themeDirectory, theme))
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			message := fmt.Sprintf("unknown theme %s not found @ %s", theme, filepath.Join(themeDirectory, theme))
+
+			// suggest the closest theme installed in the theme directory
+			if match, ok := closest(theme, entries(themeDirectory, true)); ok {
+				message = fmt.Sprintf("%s (did you mean %s?)", message, match)
+			}
+
+			s.problem("theme", message)
+		}
+	}
+
+	// check if the language codes are valid
+	s.validateLanguageCode("languageCode", s.LanguageCode)
+	s.validateMenus("menus", s.Menus)
+
+	for _, lang := range sortedKeys(s.Languages) {
+		s.validateLanguageCode(fmt.Sprintf("languages.%s.languageCode", lang), s.Languages[lang].LanguageCode)
+		s.validateMenus(fmt.Sprintf("languages.%s.menus", lang), s.Languages[lang].Menus)
+	}
+
+	// check if the default content language is configured
+	if len(s.DefaultContentLanguage) > 0 && len(s.Languages) > 0 {
+		if _, ok := s.Languages[strings.ToLower(s.DefaultContentLanguage)]; !ok {
+			s.problem("defaultContentLanguage", fmt.Sprintf("language %s not found in languages", s.DefaultContentLanguage))
+		}
+	}
+
+	v := new(ValidationError)
+
+	// the site configuration is read from the config_file and config_directory
+	for _, problem := range s.problems {
+		v.add("config_file", "", "%s", problem)
+	}
+
+	return v.err()
+}
+
+// validateLanguageCode verifies the language code is a valid BCP 47 tag.
+func (s *SiteConfig) validateLanguageCode(key, code string) {
+	if len(code) == 0 {
+		return
+	}
+
+	_, err := language.Parse(code)
+	if err != nil {
+		s.problem(key, fmt.Sprintf("invalid language code %q (expected a BCP 47 tag, e.g. en-us)", code))
+	}
+}
+
+// validateMenus verifies the menu entries are well formed.
+func (s *SiteConfig) validateMenus(key string, menus map[string][]*MenuEntry) {
+	for _, menu := range sortedKeys(menus) {
+		entries := menus[menu]
+
+		// track the identifiers for the menu entries
+		identifiers := make(map[string]bool)
+
+		for i, entry := range entries {
+			entryKey := fmt.Sprintf("%s.%s[%d]", key, menu, i)
+
+			id := entry.Identifier
+			if len(id) == 0 {
+				id = entry.Name
+			}
+
+			if len(id) == 0 && len(entry.PageRef) == 0 {
+				s.problem(entryKey, "menu entry has no name, identifier or pageRef")
+
+				continue
+			}
+
+			if identifiers[id] {
+				s.problem(entryKey, fmt.Sprintf("duplicate menu entry identifier %s", id))
+			}
+
+			identifiers[id] = true
+		}
+
+		for i, entry := range entries {
+			if len(entry.Parent) > 0 && !identifiers[entry.Parent] {
+				s.problem(fmt.Sprintf("%s.%s[%d].parent", key, menu, i), fmt.Sprintf("parent %s not found in menu %s", entry.Parent, menu))
+			}
+		}
+	}
+}
+
+// problem records a problem for the key in the site configuration.
+func (s *SiteConfig) problem(key, message string) {
+	s.problems = append(s.problems, fmt.Sprintf("%s: %s: %s", s.source(key), key, message))
+}
+
+// source returns the file the key was read from.
+func (s *SiteConfig) source(key string) string {
+	key = strings.ToLower(key)
+
+	for len(key) > 0 {
+		if path, ok := s.sources[key]; ok {
+			return path
+		}
+
+		// fall back to the parent key
+		i := strings.LastIndexAny(key, ".[")
+		if i < 0 {
+			break
+		}
+
+		key = key[:i]
+	}
+
+	// fall back to the root configuration
+	if len(s.Files) > 0 {
+		return s.Files[0]
+	}
+
+	return "site configuration"
+}
+
+// stringValue returns the value as a string.
+func stringValue(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(value)
+}
+
+// stringsValue returns the value as a list of strings.
+func stringsValue(value any) []string {
+	switch value := value.(type) {
+	case string:
+		if len(value) == 0 {
+			return nil
+		}
+
+		return []string{value}
+	case []any:
+		var values []string
+
+		for _, v := range value {
+			values = append(values, stringValue(v))
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// intValue returns the value as an integer.
+func intValue(value any) (int, bool) {
+	switch value := value.(type) {
+	case nil:
+		return 0, true
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		return int(value), value == float64(int(value))
+	}
+
+	return 0, false
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestConfig_Site(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure bool
+		name    string
+		config  Config
+		files   map[string]string
+		want    *SiteConfig
+	}{
+		{
+			failure: true,
+			name:    "no site configuration",
+			config:  Config{SourceDirectory: "/site", Directory: "config"},
+			files:   map[string]string{},
+		},
+		{
+			failure: true,
+			name:    "malformed site configuration",
+			config:  Config{SourceDirectory: "/site", Directory: "config"},
+			files: map[string]string{
+				"/site/hugo.toml": `baseURL = `,
+			},
+		},
+		{
+			failure: false,
+			name:    "root site configuration",
+			config:  Config{SourceDirectory: "/site", Directory: "config"},
+			files: map[string]string{
+				"/site/hugo.toml": `baseURL = "https://example.com/"
+theme = "docsy"
+languageCode = "en-us"`,
+			},
+			want: &SiteConfig{
+				BaseURL:      "https://example.com/",
+				Themes:       []string{"docsy"},
+				LanguageCode: "en-us",
+				Files:        []string{"/site/hugo.toml"},
+			},
+		},
+		{
+			failure: false,
+			name:    "config directory with environment overlay",
+			config:  Config{SourceDirectory: "/site", Directory: "config", Environment: "staging"},
+			files: map[string]string{
+				"/site/config/_default/hugo.yaml": `baseURL: https://example.com/
+theme: [docsy, extras]`,
+				"/site/config/_default/menus.en.toml": `[[main]]
+name = "Docs"
+url = "/docs/"
+weight = 10`,
+				"/site/config/_default/languages.json": `{"en": {"languageCode": "en-us"}}`,
+				"/site/config/staging/hugo.toml":       `baseURL = "https://staging.example.com/"`,
+				"/site/config/production/hugo.toml":    `baseURL = "https://www.example.com/"`,
+			},
+			want: &SiteConfig{
+				BaseURL: "https://staging.example.com/",
+				Themes:  []string{"docsy", "extras"},
+				Languages: map[string]*SiteLanguage{
+					"en": {
+						LanguageCode: "en-us",
+						Menus: map[string][]*MenuEntry{
+							"main": {{Name: "Docs", URL: "/docs/", Weight: 10}},
+						},
+					},
+				},
+				Files: []string{
+					"/site/config/_default/hugo.yaml",
+					"/site/config/_default/languages.json",
+					"/site/config/_default/menus.en.toml",
+					"/site/config/staging/hugo.toml",
+				},
+			},
+		},
+		{
+			failure: false,
+			name:    "custom config file",
+			config:  Config{SourceDirectory: "/site", Directory: "config", File: "site.json"},
+			files: map[string]string{
+				"/site/hugo.toml": `baseURL = "https://example.com/"`,
+				"/site/site.json": `{"baseURL": "https://custom.example.com/"}`,
+			},
+			want: &SiteConfig{
+				BaseURL: "https://custom.example.com/",
+				Files:   []string{"/site/site.json"},
+			},
+		},
+		{
+			failure: false,
+			name:    "config overrides",
+			config: Config{
+				SourceDirectory: "/site",
+				Directory:       "config",
+				Overrides:       map[string]any{"baseURL": "https://preview.example.com/"},
+			},
+			files: map[string]string{
+				"/site/hugo.toml": `baseURL = "https://example.com/"
+theme = "docsy"`,
+			},
+			want: &SiteConfig{
+				BaseURL: "https://preview.example.com/",
+				Themes:  []string{"docsy"},
+				Files:   []string{"/site/hugo.toml"},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		a := &afero.Afero{Fs: appFS}
+
+		for path, content := range test.files {
+			err := a.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				t.Errorf("unable to create file %s: %v", path, err)
+			}
+		}
+
+		got, err := test.config.Site()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("%s Site should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Site returned err: %v", test.name, err)
+
+			continue
+		}
+
+		// ignore the internal state of the configuration
+		got.raw, got.sources = nil, nil
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s Site is %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSiteConfig_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		baseURL string
+		files   map[string]string
+		want    []string
+	}{
+		{
+			name: "valid site configuration",
+			files: map[string]string{
+				"/site/hugo.toml": `baseURL = "https://example.com/"
+theme = ["docsy", "github.com/example/theme"]
+languageCode = "en-us"
+
+[[menus.main]]
+name = "Docs"
+weight = 10
+
+[[menus.main]]
+name = "Guides"
+parent = "Docs"`,
+				"/site/themes/docsy/theme.toml": ``,
+			},
+		},
+		{
+			name:    "base url provided by parameter",
+			baseURL: "https://example.com/",
+			files: map[string]string{
+				"/site/hugo.toml": `title = "Example"`,
+			},
+		},
+		{
+			name: "common mistakes",
+			files: map[string]string{
+				"/site/hugo.toml": `theme = "docs"
+languageCode = "en_US!"
+defaultContentLanguage = "de"`,
+				"/site/config/_default/languages.toml": `[en]
+languageCode = "en-us"`,
+				"/site/config/_default/menus.toml": `[[main]]
+name = "Docs"
+weight = "first"
+
+[[main]]
+name = "Docs"
+
+[[main]]
+name = "Guides"
+parent = "Documentation"
+
+[[main]]
+url = "/about/"`,
+			},
+			want: []string{
+				"/site/config/_default/menus.toml: menus.main[0].weight: expected an integer but got first",
+				"/site/hugo.toml: baseURL: no baseURL provided in the site configuration or the base_url parameter",
+				"/site/hugo.toml: theme: unknown theme docs not found @ /site/themes/docs",
+				`/site/hugo.toml: languageCode: invalid language code "en_US!" (expected a BCP 47 tag, e.g. en-us)`,
+				"/site/config/_default/menus.toml: menus.main[1]: duplicate menu entry identifier Docs",
+				"/site/config/_default/menus.toml: menus.main[3]: menu entry has no name, identifier or pageRef",
+				"/site/config/_default/menus.toml: menus.main[2].parent: parent Documentation not found in menu main",
+				"/site/hugo.toml: defaultContentLanguage: language de not found in languages",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		a := &afero.Afero{Fs: appFS}
+
+		for path, content := range test.files {
+			err := a.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				t.Errorf("unable to create file %s: %v", path, err)
+			}
+		}
+
+		c := &Config{SourceDirectory: "/site", Directory: "config"}
+
+		site, err := c.Site()
+		if err != nil {
+			t.Errorf("%s Site returned err: %v", test.name, err)
+
+			continue
+		}
+
+		err = site.Validate(test.baseURL, "/site/themes")
+
+		if len(test.want) > 0 {
+			if err == nil {
+				t.Errorf("%s Validate should have returned err", test.name)
+
+				continue
+			}
+
+			var got []string
+
+			for _, problem := range err.(*ValidationError).Problems {
+				got = append(got, problem.Message)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s Validate is %q, want %q", test.name, got, test.want)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s Validate returned err: %v", test.name, err)
+		}
+	}
+}
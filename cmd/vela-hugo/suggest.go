@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// closest returns the candidate with the smallest edit distance to the value,
+// only returning a candidate which is close enough to be a likely typo.
+func closest(value string, candidates []string) (string, bool) {
+	var (
+		match string
+		best  = -1
+	)
+
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+
+		if best < 0 || distance < best {
+			match, best = candidate, distance
+		}
+	}
+
+	// allow roughly one typo for every three characters
+	limit := max(2, len(value)/3)
+
+	if best < 0 || best > limit {
+		return "", false
+	}
+
+	return match, true
+}
+
+// editDistance returns the Levenshtein distance between the values,
+// which is the number of single character edits to change one into the other.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	// capture the distances for the previous and current row
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+// entries returns the sorted names of the directories (or files) in the directory.
+func entries(dir string, directories bool) []string {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	infos, err := a.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, info := range infos {
+		// skip hidden entries, e.g. .git
+		if strings.HasPrefix(info.Name(), ".") || info.IsDir() != directories {
+			continue
+		}
+
+		names = append(names, info.Name())
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// siteConfigCandidates returns the sorted paths of the config files
+// in the directory and its subdirectories, relative to the base.
+func siteConfigCandidates(base, dir string) []string {
+	var files []string
+
+	_ = afero.Walk(appFS, dir, func(path string, info fs.FileInfo, err error) error {
+		// skip entries which can't be read
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		if !slices.Contains(siteConfigFormats, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, rel)
+
+		return nil
+	})
+
+	slices.Sort(files)
+
+	return files
+}
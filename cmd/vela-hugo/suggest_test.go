@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_editDistance(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "docsy", b: "", want: 5},
+		{a: "docsy", b: "docsy", want: 0},
+		{a: "docsy", b: "doscy", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "hugo.toml", b: "hugo.yaml", want: 2},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := editDistance(test.a, test.b)
+
+		if got != test.want {
+			t.Errorf("editDistance(%s, %s) is %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func Test_closest(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name       string
+		value      string
+		candidates []string
+		want       string
+		ok         bool
+	}{
+		{
+			name:       "no candidates",
+			value:      "docsy",
+			candidates: nil,
+		},
+		{
+			name:       "typo",
+			value:      "docys",
+			candidates: []string{"ananke", "docsy", "PaperMod"},
+			want:       "docsy",
+			ok:         true,
+		},
+		{
+			name:       "different case",
+			value:      "papermod",
+			candidates: []string{"ananke", "docsy", "PaperMod"},
+			want:       "PaperMod",
+			ok:         true,
+		},
+		{
+			name:       "no close candidate",
+			value:      "hextra",
+			candidates: []string{"ananke", "docsy", "PaperMod"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, ok := closest(test.value, test.candidates)
+
+		if got != test.want || ok != test.ok {
+			t.Errorf("%s closest is %s (%t), want %s (%t)", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func Test_siteConfigCandidates(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	for _, path := range []string{
+		"/site/config/_default/hugo.toml",
+		"/site/config/_default/menus.en.yaml",
+		"/site/config/production/params.json",
+		"/site/config/README.md",
+	} {
+		_, err := appFS.Create(path)
+		if err != nil {
+			t.Errorf("unable to create file %s: %v", path, err)
+		}
+	}
+
+	want := []string{
+		"config/_default/hugo.toml",
+		"config/_default/menus.en.yaml",
+		"config/production/params.json",
+	}
+
+	got := siteConfigCandidates("/site", "/site/config")
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("siteConfigCandidates is %v, want %v", got, want)
+	}
+}
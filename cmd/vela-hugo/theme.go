@@ -4,11 +4,10 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/afero"
 )
 
 // Theme represents the plugin configuration for what Hugo theme(s) to use.
@@ -19,47 +18,54 @@ type Theme struct {
 	Directory string
 }
 
-// Validate verifies the Theme is properly configured.
-func (t *Theme) Validate() error {
+// Validate verifies the Theme is properly configured
+// with the theme directory resolved from the source directory.
+func (t *Theme) Validate(source string) error {
 	logrus.Trace("validating theme configuration")
 
-	// use custom filesystem which enables us to test
-	a := &afero.Afero{
-		Fs: appFS,
-	}
+	v := new(ValidationError)
 
 	// check if a theme is provided
 	if len(t.Name) > 0 {
 		// verify theme directory is provided
 		if len(t.Directory) == 0 {
-			return fmt.Errorf("no theme directory provided")
+			v.add("theme_directory", "set theme_directory, e.g. themes", "no theme directory provided")
+
+			return v.err()
 		}
 
+		dir := resolvePath(source, t.Directory)
+
 		// check if theme directory exists
-		_, err := a.Stat(t.Directory)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no theme directory found @ %s", t.Directory)
-			}
-
-			return err
+		if !v.stat("theme_directory", "theme directory", dir, "create the directory or update theme_directory") {
+			return v.err()
 		}
 
 		// create path to theme based off directory and name
-		path := filepath.Join(t.Directory, t.Name)
+		path := filepath.Join(dir, t.Name)
 
 		// check if theme path exists
-		_, err = a.Stat(path)
-		if err != nil {
-			// check if a not exist err was returned
-			if os.IsNotExist(err) {
-				return fmt.Errorf("no theme found @ %s", path)
-			}
-
-			return err
-		}
+		v.stat("theme_name", "theme", path, t.suggestion(dir))
+	}
+
+	return v.err()
+}
+
+// suggestion returns the suggested fix for a theme which doesn't exist,
+// listing the themes in the theme directory and the closest match.
+func (t *Theme) suggestion(dir string) string {
+	themes := entries(dir, true)
+
+	// check if any themes are installed
+	if len(themes) == 0 {
+		return fmt.Sprintf("install the theme, no themes found in %s", t.Directory)
+	}
+
+	available := strings.Join(themes, ", ")
+
+	if match, ok := closest(t.Name, themes); ok {
+		return fmt.Sprintf("did you mean theme_name: %s? (available: %s)", match, available)
 	}
 
-	return nil
+	return fmt.Sprintf("install the theme or use one of: %s", available)
 }
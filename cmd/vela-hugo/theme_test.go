@@ -82,7 +82,7 @@ func TestTheme_Validate(t *testing.T) {
 			}
 		}
 
-		err := test.theme.Validate()
+		err := test.theme.Validate("")
 
 		if test.failure {
 			if err == nil {
@@ -97,3 +97,51 @@ func TestTheme_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestTheme_suggestion(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		theme  Theme
+		themes []string
+		want   string
+	}{
+		{
+			name:   "no themes installed",
+			theme:  Theme{Name: "docsy", Directory: "themes"},
+			themes: nil,
+			want:   "install the theme, no themes found in themes",
+		},
+		{
+			name:   "closest theme",
+			theme:  Theme{Name: "docys", Directory: "themes"},
+			themes: []string{"ananke", "docsy"},
+			want:   "did you mean theme_name: docsy? (available: ananke, docsy)",
+		},
+		{
+			name:   "no close theme",
+			theme:  Theme{Name: "hextra", Directory: "themes"},
+			themes: []string{"ananke", "docsy"},
+			want:   "install the theme or use one of: ananke, docsy",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		// setup in mem file system
+		appFS = afero.NewMemMapFs()
+
+		for _, theme := range test.themes {
+			err := appFS.MkdirAll(filepath.Join("/site/themes", theme), 0777)
+			if err != nil {
+				t.Errorf("unable to create theme %s: %v", theme, err)
+			}
+		}
+
+		got := test.theme.suggestion("/site/themes")
+
+		if got != test.want {
+			t.Errorf("%s suggestion is %s, want %s", test.name, got, test.want)
+		}
+	}
+}
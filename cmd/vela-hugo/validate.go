@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/afero"
+)
+
+// Problem represents an invalid parameter found when validating the plugin.
+type Problem struct {
+	// name of the parameter the problem was found for
+	Parameter string
+	// description of the problem
+	Message string
+	// suggested fix for the problem
+	Suggestion string
+}
+
+// ValidationError represents every problem found when validating the plugin.
+type ValidationError struct {
+	Problems []*Problem
+}
+
+// Error renders the problems as a table of parameters.
+func (v *ValidationError) Error() string {
+	b := new(strings.Builder)
+
+	fmt.Fprintf(b, "invalid plugin configuration (%d problem(s)):\n\n", len(v.Problems))
+
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "  PARAMETER\tPROBLEM\tSUGGESTION")
+
+	for _, problem := range v.Problems {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", orNone(problem.Parameter), problem.Message, orNone(problem.Suggestion))
+	}
+
+	w.Flush()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// add captures a problem for the parameter.
+func (v *ValidationError) add(parameter, suggestion, format string, args ...any) {
+	v.Problems = append(v.Problems, &Problem{
+		Parameter:  parameter,
+		Message:    fmt.Sprintf(format, args...),
+		Suggestion: suggestion,
+	})
+}
+
+// merge captures the problems from the error, which
+// is captured as a single problem for other errors.
+func (v *ValidationError) merge(err error) {
+	if err == nil {
+		return
+	}
+
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		v.Problems = append(v.Problems, validation.Problems...)
+
+		return
+	}
+
+	v.Problems = append(v.Problems, &Problem{Message: err.Error()})
+}
+
+// err returns the problems as an error when any were found.
+func (v *ValidationError) err() error {
+	if len(v.Problems) == 0 {
+		return nil
+	}
+
+	return v
+}
+
+// stat captures a problem for the parameter when the path doesn't exist.
+func (v *ValidationError) stat(parameter, kind, path, suggestion string) bool {
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	_, err := a.Stat(path)
+	if err != nil {
+		// check if a not exist err was returned
+		if os.IsNotExist(err) {
+			v.add(parameter, suggestion, "no %s found @ %s", kind, path)
+
+			return false
+		}
+
+		v.add(parameter, "", "%v", err)
+
+		return false
+	}
+
+	return true
+}
+
+// tableErr prints the problems from the error as a table, since
+// the log output escapes newlines, and returns a summary error.
+func tableErr(err error) error {
+	var v *ValidationError
+
+	if !errors.As(err, &v) {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, v.Error())
+
+	return fmt.Errorf("invalid plugin configuration: %d problem(s) found", len(v.Problems))
+}
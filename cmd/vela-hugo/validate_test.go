@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/afero"
+)
+
+func TestValidationError_Error(t *testing.T) {
+	// setup types
+	v := new(ValidationError)
+
+	v.add("theme_name", "install the theme or update theme_name", "no theme found @ %s", "themes/docsy")
+	v.merge(errors.New("permission denied"))
+
+	want := `invalid plugin configuration (2 problem(s)):
+
+  PARAMETER   PROBLEM                        SUGGESTION
+  theme_name  no theme found @ themes/docsy  install the theme or update theme_name
+  -           permission denied              -`
+
+	if got := v.Error(); got != want {
+		t.Errorf("Error is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPlugin_Validate_Problems(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	p := &Plugin{
+		Artifact: &Artifact{Enabled: true, Format: "rar"},
+		Build:    &Build{BaseURL: "docs.example.com"},
+		Config: &Config{
+			CacheDirectory:   "/cache",
+			ContentDirectory: "/content",
+		},
+		Module: &Module{},
+		NPM:    &NPM{},
+		Output: &Output{Budgets: []string{"**/*.js 200KB"}},
+		SBOM:   &SBOM{Enabled: true, Format: "swid"},
+		Theme:  &Theme{Name: "docsy"},
+	}
+
+	err := p.Validate()
+
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("Validate returned %v, want *ValidationError", err)
+	}
+
+	var got []string
+
+	for _, problem := range v.Problems {
+		got = append(got, problem.Parameter)
+	}
+
+	want := []string{"artifact_format", "base_url", "cache_directory", "content_directory", "budgets", "sbom_format", "theme_directory"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate problems are %v, want %v", got, want)
+	}
+}
+
+func TestPlugin_Validate_SiteProblems(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/site/hugo.toml", []byte(`languageCode = "english"`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	p := &Plugin{
+		Artifact: &Artifact{},
+		Build:    &Build{},
+		Config: &Config{
+			Directory:       "config",
+			SourceDirectory: "/site",
+			ValidateSite:    true,
+		},
+		Module: &Module{},
+		NPM:    &NPM{},
+		Output: &Output{Budgets: []string{"**/*.js 200KB"}},
+		SBOM:   &SBOM{},
+		Theme:  &Theme{},
+	}
+
+	err = p.Validate()
+
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("Validate returned %v, want *ValidationError", err)
+	}
+
+	// the site problems are reported along with the parameter problems
+	want := []string{
+		"budgets",
+		"config_file: /site/hugo.toml: baseURL: no baseURL provided in the site configuration or the base_url parameter",
+		`config_file: /site/hugo.toml: languageCode: invalid language code "english" (expected a BCP 47 tag, e.g. en-us)`,
+	}
+
+	var got []string
+
+	for _, problem := range v.Problems {
+		if problem.Parameter == "config_file" {
+			got = append(got, problem.Parameter+": "+problem.Message)
+		} else {
+			got = append(got, problem.Parameter)
+		}
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate problems are %q, want %q", got, want)
+	}
+}
+
+func TestPlugin_Validate_BaseURLOverride(t *testing.T) {
+	// setup in mem file system
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/site/hugo.toml", []byte(`baseURL = "https://example.com/"`), 0644)
+	if err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+
+	err = a.MkdirAll("/empty", 0755)
+	if err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+
+	// capture the logged warnings
+	hook := test.NewGlobal()
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{name: "site configuration", source: "/site", want: 1},
+		{name: "missing site configuration", source: "/empty", want: 0},
+	}
+
+	for _, test := range tests {
+		hook.Reset()
+
+		p := &Plugin{
+			Artifact: &Artifact{},
+			Build:    &Build{BaseURL: "https://docs.example.com/"},
+			Config: &Config{
+				Directory:       "config",
+				SourceDirectory: test.source,
+			},
+			Module: &Module{},
+			NPM:    &NPM{},
+			Output: &Output{},
+			SBOM:   &SBOM{},
+			Theme:  &Theme{},
+		}
+
+		err = p.Validate()
+		if err != nil {
+			t.Errorf("%s Validate returned err: %v", test.name, err)
+		}
+
+		var got int
+
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "overrides baseURL") {
+				got++
+			}
+		}
+
+		if got != test.want {
+			t.Errorf("%s Validate logged %d base url warning(s), want %d", test.name, got, test.want)
+		}
+	}
+}